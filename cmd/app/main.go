@@ -4,16 +4,31 @@ import (
 	"AuthService/internal/app"
 	"context"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	a, err := app.NewApp(ctx)
 	if err != nil {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
 
+	go a.RunKeysReloader(ctx)
+	go a.RunRevocationCache(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.RunTokenJanitor(ctx)
+	}()
+
 	go func() {
 		if err := a.RunMetrics(); err != nil {
 			log.Fatalf("Failed to run metrics: %v", err)
@@ -21,8 +36,21 @@ func main() {
 		log.Print(err)
 	}()
 
+	go func() {
+		if err := a.RunGateway(); err != nil {
+			log.Fatalf("Failed to run HTTP gateway: %v", err)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		a.Stop()
+	}()
+
 	err = a.Run()
 	if err != nil {
 		log.Fatalf("Failed to run: %v", err)
 	}
+
+	wg.Wait()
 }
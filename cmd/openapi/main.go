@@ -0,0 +1,17 @@
+package main
+
+import (
+	"AuthService/internal/api"
+	"encoding/json"
+	"log"
+	"os"
+)
+
+func main() {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(api.OpenAPIDocument()); err != nil {
+		log.Fatalf("Failed to write OpenAPI document: %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE credentials ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;  -- Подтверждена ли почта пользователя
+UPDATE credentials SET email_verified = TRUE;                                       -- Уже существующие аккаунты считаем подтвержденными
+
+CREATE TABLE email_confirmation_code (
+    id SERIAL PRIMARY KEY,
+    credentials_id INTEGER NOT NULL,                  -- Внешний ключ на пользователя
+    code VARCHAR(6) NOT NULL,                         -- Код подтверждения
+    attempts INTEGER NOT NULL DEFAULT 0,              -- Количество неудачных попыток ввода
+    used BOOLEAN NOT NULL DEFAULT FALSE,              -- Флаг, указывающий на то, был ли код использован или отозван
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время создания кода
+    expires_at TIMESTAMP NOT NULL,                    -- Время истечения кода
+    CONSTRAINT fk_credentials FOREIGN KEY (credentials_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_email_confirmation_code_credentials_id ON email_confirmation_code (credentials_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE email_confirmation_code;
+ALTER TABLE credentials DROP COLUMN email_verified;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE password_reset_token (
+    id SERIAL PRIMARY KEY,
+    credentials_id INTEGER NOT NULL,                  -- Внешний ключ на пользователя
+    token_hash VARCHAR(64) NOT NULL UNIQUE,           -- SHA-256 от токена сброса пароля
+    used BOOLEAN NOT NULL DEFAULT FALSE,              -- Флаг, указывающий на то, был ли токен использован или отозван
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время создания токена
+    expires_at TIMESTAMP NOT NULL,                    -- Время истечения токена
+    CONSTRAINT fk_credentials FOREIGN KEY (credentials_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE password_reset_token;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE issued_jwt_token ADD COLUMN family_id VARCHAR(36);                 -- Семейство токенов, общее для всех пар одной цепочки обновлений
+ALTER TABLE issued_jwt_token ADD COLUMN rotated BOOLEAN NOT NULL DEFAULT FALSE; -- Флаг, указывающий на то, что refresh токен уже был обменян на новую пару
+UPDATE issued_jwt_token SET family_id = jti WHERE family_id IS NULL;
+ALTER TABLE issued_jwt_token ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX idx_issued_jwt_token_family_id ON issued_jwt_token (family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_issued_jwt_token_family_id;
+ALTER TABLE issued_jwt_token DROP COLUMN rotated;
+ALTER TABLE issued_jwt_token DROP COLUMN family_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions (
+    id VARCHAR(36) PRIMARY KEY,                       -- Идентификатор сессии, совпадает с family_id токенов
+    subject_id INTEGER NOT NULL,                      -- Внешний ключ на пользователя
+    user_agent TEXT NOT NULL DEFAULT '',              -- User-Agent клиента
+    ip VARCHAR(45) NOT NULL DEFAULT '',               -- IP адрес клиента
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время входа
+    last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- Время последнего обновления токенов
+    expires_at TIMESTAMP NOT NULL,                    -- Время истечения текущего refresh токена
+    CONSTRAINT fk_user FOREIGN KEY (subject_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_subject_id ON sessions (subject_id);
+
+INSERT INTO sessions (id, subject_id, created_at, last_used_at, expires_at)
+SELECT family_id, subject_id, MIN(issued_at), MAX(issued_at), COALESCE(MAX(expires_at), CURRENT_TIMESTAMP)
+FROM issued_jwt_token
+GROUP BY family_id, subject_id;
+
+ALTER TABLE issued_jwt_token ADD CONSTRAINT fk_session FOREIGN KEY (family_id) REFERENCES sessions (id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE issued_jwt_token DROP CONSTRAINT fk_session;
+DROP TABLE sessions;
+-- +goose StatementEnd
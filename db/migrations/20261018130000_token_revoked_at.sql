@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE issued_jwt_token ADD COLUMN revoked_at TIMESTAMP;  -- Время отзыва токена
+UPDATE issued_jwt_token SET revoked_at = CURRENT_TIMESTAMP WHERE revoked;
+
+CREATE INDEX idx_issued_jwt_token_revoked_at ON issued_jwt_token (revoked_at) WHERE revoked;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_issued_jwt_token_revoked_at;
+ALTER TABLE issued_jwt_token DROP COLUMN revoked_at;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Старые строки без expires_at удаляются очисткой, когда с их отзыва прошло больше срока жизни refresh токена
+CREATE INDEX idx_issued_jwt_token_expires_at ON issued_jwt_token (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_issued_jwt_token_expires_at;
+-- +goose StatementEnd
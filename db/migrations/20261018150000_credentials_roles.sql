@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE credentials ADD COLUMN roles TEXT NOT NULL DEFAULT 'user';  -- Роли пользователя через запятую
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE credentials DROP COLUMN roles;
+-- +goose StatementEnd
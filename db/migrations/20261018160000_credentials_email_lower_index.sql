@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Почта сравнивается без учета регистра, новые адреса сохраняются в нижнем регистре
+CREATE INDEX idx_credentials_email_lower ON credentials (LOWER(email));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_credentials_email_lower;
+-- +goose StatementEnd
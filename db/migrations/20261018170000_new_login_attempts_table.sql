@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE login_attempts (
+    key VARCHAR(300) PRIMARY KEY,          -- Ключ счетчика: email:<адрес> или ip:<адрес>
+    failures INTEGER NOT NULL DEFAULT 0,   -- Число неудачных попыток подряд
+    last_failure_at TIMESTAMP,             -- Время последней неудачной попытки
+    locked_until TIMESTAMP                 -- До какого времени вход запрещен
+);
+
+CREATE INDEX idx_login_attempts_last_failure_at ON login_attempts (last_failure_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE login_attempts;
+-- +goose StatementEnd
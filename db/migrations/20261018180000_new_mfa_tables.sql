@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE credentials_totp (
+    credentials_id INTEGER PRIMARY KEY,               -- Внешний ключ на пользователя
+    secret_encrypted TEXT NOT NULL,                   -- Секрет TOTP, зашифрованный AES-GCM
+    confirmed_at TIMESTAMP,                           -- Время подтверждения, до него второй фактор не действует
+    last_used_step BIGINT NOT NULL DEFAULT 0,         -- Последний принятый шаг TOTP, защита от повторного использования кода
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время создания секрета
+    CONSTRAINT fk_credentials FOREIGN KEY (credentials_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+
+CREATE TABLE recovery_codes (
+    id SERIAL PRIMARY KEY,
+    credentials_id INTEGER NOT NULL,                  -- Внешний ключ на пользователя
+    code_hash VARCHAR(64) NOT NULL,                   -- SHA-256 от кода восстановления
+    used BOOLEAN NOT NULL DEFAULT FALSE,              -- Флаг, указывающий на то, был ли код использован
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время создания кода
+    CONSTRAINT fk_credentials FOREIGN KEY (credentials_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_recovery_codes_credentials_id ON recovery_codes (credentials_id);
+
+CREATE TABLE mfa_challenges (
+    id SERIAL PRIMARY KEY,
+    credentials_id INTEGER NOT NULL,                  -- Внешний ключ на пользователя
+    token_hash VARCHAR(64) NOT NULL UNIQUE,           -- SHA-256 от токена MFA челленджа
+    attempts INTEGER NOT NULL DEFAULT 0,              -- Количество попыток ввода кода
+    used BOOLEAN NOT NULL DEFAULT FALSE,              -- Флаг, указывающий на то, был ли челлендж использован или отозван
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время создания челленджа
+    expires_at TIMESTAMP NOT NULL,                    -- Время истечения челленджа
+    CONSTRAINT fk_credentials FOREIGN KEY (credentials_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE mfa_challenges;
+DROP TABLE recovery_codes;
+DROP TABLE credentials_totp;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE email_login_code (
+    id SERIAL PRIMARY KEY,
+    credentials_id INTEGER NOT NULL,                  -- Внешний ключ на пользователя
+    code_hash VARCHAR(64) NOT NULL,                   -- SHA-256 от кода входа
+    attempts INTEGER NOT NULL DEFAULT 0,              -- Количество неудачных попыток ввода
+    used BOOLEAN NOT NULL DEFAULT FALSE,              -- Флаг, указывающий на то, был ли код использован или отозван
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,   -- Время создания кода
+    expires_at TIMESTAMP NOT NULL,                    -- Время истечения кода
+    CONSTRAINT fk_credentials FOREIGN KEY (credentials_id) REFERENCES credentials (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_email_login_code_credentials_id ON email_login_code (credentials_id);
+
+ALTER TABLE sessions ADD COLUMN login_method VARCHAR(20) NOT NULL DEFAULT 'password';        -- Способ входа, которым создана сессия
+ALTER TABLE mfa_challenges ADD COLUMN login_method VARCHAR(20) NOT NULL DEFAULT 'password';  -- Способ входа, пройденный до второго фактора
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE mfa_challenges DROP COLUMN login_method;
+ALTER TABLE sessions DROP COLUMN login_method;
+DROP TABLE email_login_code;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE email_confirmation_code RENAME COLUMN code TO code_hash;
+ALTER TABLE email_confirmation_code ALTER COLUMN code_hash TYPE VARCHAR(64);            -- SHA-256 от кода подтверждения
+UPDATE email_confirmation_code SET code_hash = encode(sha256(code_hash::bytea), 'hex');  -- Уже выданные коды хэшируются на месте
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM email_confirmation_code;                                                    -- Хэши нельзя вернуть к исходным кодам
+ALTER TABLE email_confirmation_code ALTER COLUMN code_hash TYPE VARCHAR(6);
+ALTER TABLE email_confirmation_code RENAME COLUMN code_hash TO code;
+-- +goose StatementEnd
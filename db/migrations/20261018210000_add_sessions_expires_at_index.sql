@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_sessions_expires_at ON sessions (expires_at);  -- Для удаления истекших сессий
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_sessions_expires_at;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Из аккаунтов, почта которых отличается только регистром, адрес сохраняет самый ранний.
+-- Остальным дописывается несуществующий домен, чтобы по ним нельзя было войти, а данные не терялись.
+UPDATE credentials c
+SET email = c.email || '.duplicate-' || c.id
+WHERE EXISTS (
+    SELECT 1 FROM credentials o
+    WHERE LOWER(o.email) = LOWER(c.email) AND o.id < c.id
+);
+
+UPDATE credentials SET email = LOWER(email) WHERE email <> LOWER(email);  -- Все адреса хранятся в нижнем регистре
+
+DROP INDEX idx_credentials_email_lower;
+CREATE UNIQUE INDEX idx_credentials_email_lower ON credentials (LOWER(email));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_credentials_email_lower;
+CREATE INDEX idx_credentials_email_lower ON credentials (LOWER(email));
+-- +goose StatementEnd
@@ -91,8 +91,26 @@ func (is *AuthImplementationSever) VerifyAccessToken(ctx context.Context, req *d
 			} else {
 				code = st.Code()
 			}
-			metrics.ObserveVerifyAccessTokenRequest(time.Since(start), code)
 		}
+		metrics.ObserveVerifyAccessTokenRequest(time.Since(start), code)
+	}()
+	return resp, err
+}
+
+func (is *AuthImplementationSever) VerifyAccessTokens(ctx context.Context, req *desc.VerifyAccessTokensRequest) (*desc.VerifyAccessTokensResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.VerifyAccessTokens(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveVerifyAccessTokensRequest(time.Since(start), code)
 	}()
 	return resp, err
 }
@@ -115,3 +133,244 @@ func (is *AuthImplementationSever) Logout(ctx context.Context, req *desc.LogoutR
 
 	return resp, err
 }
+
+func (is *AuthImplementationSever) LogoutAll(ctx context.Context, req *desc.LogoutAllRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.LogoutAll(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveLogoutAllRequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
+
+func (is *AuthImplementationSever) ConfirmSignUp(ctx context.Context, req *desc.ConfirmSignUpRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	err := is.credentialsUseCase.ConfirmSignUp(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveConfirmSignUpRequest(time.Since(start), code)
+	}()
+	return &emptypb.Empty{}, err
+}
+
+func (is *AuthImplementationSever) ResendConfirmation(ctx context.Context, req *desc.ResendConfirmationRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	err := is.credentialsUseCase.ResendConfirmation(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveResendConfirmationRequest(time.Since(start), code)
+	}()
+	return &emptypb.Empty{}, err
+}
+
+func (is *AuthImplementationSever) ResetPassword(ctx context.Context, req *desc.ResetPasswordRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	err := is.credentialsUseCase.ResetPassword(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveResetPasswordRequest(time.Since(start), code)
+	}()
+	return &emptypb.Empty{}, err
+}
+
+func (is *AuthImplementationSever) ConfirmReset(ctx context.Context, req *desc.ConfirmResetRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	err := is.credentialsUseCase.ConfirmReset(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveConfirmResetRequest(time.Since(start), code)
+	}()
+	return &emptypb.Empty{}, err
+}
+
+func (is *AuthImplementationSever) UpdatePassword(ctx context.Context, req *desc.UpdatePasswordRequest) (*desc.UpdatePasswordResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.UpdatePassword(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveUpdatePasswordRequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
+
+func (is *AuthImplementationSever) ListSessions(ctx context.Context, req *desc.ListSessionsRequest) (*desc.ListSessionsResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.ListSessions(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveListSessionsRequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
+
+func (is *AuthImplementationSever) RevokeSession(ctx context.Context, req *desc.RevokeSessionRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	err := is.credentialsUseCase.RevokeSession(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveRevokeSessionRequest(time.Since(start), code)
+	}()
+	return &emptypb.Empty{}, err
+}
+
+func (is *AuthImplementationSever) EnrollTOTP(ctx context.Context, req *desc.EnrollTOTPRequest) (*desc.EnrollTOTPResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.EnrollTOTP(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveEnrollTOTPRequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
+
+func (is *AuthImplementationSever) ConfirmTOTP(ctx context.Context, req *desc.ConfirmTOTPRequest) (*desc.ConfirmTOTPResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.ConfirmTOTP(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveConfirmTOTPRequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
+
+func (is *AuthImplementationSever) VerifyMFA(ctx context.Context, req *desc.VerifyMFARequest) (*desc.VerifyMFAResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.VerifyMFA(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveVerifyMFARequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
+
+func (is *AuthImplementationSever) RequestLoginCode(ctx context.Context, req *desc.RequestLoginCodeRequest) (*emptypb.Empty, error) {
+	start := time.Now()
+	err := is.credentialsUseCase.RequestLoginCode(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveRequestLoginCodeRequest(time.Since(start), code)
+	}()
+	return &emptypb.Empty{}, err
+}
+
+func (is *AuthImplementationSever) SignInWithCode(ctx context.Context, req *desc.SignInWithCodeRequest) (*desc.SignInResponse, error) {
+	start := time.Now()
+	resp, err := is.credentialsUseCase.SignInWithCode(ctx, req)
+	defer func() {
+		code := codes.OK
+		if err != nil {
+			st, ok := status.FromError(err)
+			if !ok {
+				code = codes.Internal
+			} else {
+				code = st.Code()
+			}
+		}
+		metrics.ObserveSignInWithCodeRequest(time.Since(start), code)
+	}()
+
+	return resp, err
+}
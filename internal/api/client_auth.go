@@ -0,0 +1,79 @@
+package api
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/metrics"
+	"AuthService/internal/utils"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Имена эндпоинтов introspect/revoke для лимитов RATE_LIMIT_METHODS
+const (
+	IntrospectMethodName = "Introspect"
+	RevokeMethodName     = "Revoke"
+)
+
+type rateLimiter interface {
+	Allow(method string, caller string) (bool, time.Duration)
+}
+
+// rateLimitOAuth ограничивает частоту запросов к эндпоинту тем же лимитером, что и RPC,
+// иначе client_secret можно было бы перебирать без ограничений
+func rateLimitOAuth(limiter rateLimiter, methodName string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller := utils.ClientInfoFromContext(gatewayContext(r)).IP
+		if len(caller) == 0 {
+			caller = "unknown"
+		}
+
+		if ok, wait := limiter.Allow(methodName, caller); !ok {
+			metrics.IncRateLimitRejected(methodName)
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Round(time.Second).Seconds())+1))
+			writeOAuthError(w, http.StatusTooManyRequests, "temporarily_unavailable")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticateClient проверяет учетные данные клиента из заголовка Basic
+// или из полей формы client_id/client_secret (RFC 6749, раздел 2.3.1).
+func authenticateClient(r *http.Request, clients *config.OAuthClientsConfig) (string, bool) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+	if len(id) == 0 || len(secret) == 0 {
+		return "", false
+	}
+
+	expected, ok := clients.Clients[id]
+	if !ok {
+		return "", false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		return "", false
+	}
+
+	return id, true
+}
+
+func writeOAuthError(w http.ResponseWriter, code int, oauthError string) {
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="auth-service"`)
+	}
+	writeJSON(w, code, map[string]string{"error": oauthError})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"AuthService/internal/config"
+	desc "AuthService/pkg/api/v1"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	cookieSessionPath = "/v1/cookie/"
+
+	refreshTokenCookieName = "refresh_token"
+	csrfTokenCookieName    = "csrf_token"
+	csrfTokenHeaderName    = "X-CSRF-Token"
+)
+
+var csrfTokenMismatch = status.Error(codes.PermissionDenied, "CSRF token mismatch")
+
+// CookieSession - режим для браузерных клиентов: refresh токен живет только в HttpOnly cookie,
+// ограниченной путем /v1/cookie/, а запросы с этой cookie защищены double-submit CSRF токеном.
+// Access токен по-прежнему возвращается в теле ответа.
+type CookieSession struct {
+	server          desc.AuthServer
+	interceptor     grpc.UnaryServerInterceptor
+	tokens          tokenRevoker
+	cfg             *config.SessionCookieConfig
+	refreshLifeTime time.Duration
+}
+
+func NewCookieSession(server desc.AuthServer, interceptor grpc.UnaryServerInterceptor, tokens tokenRevoker, cfg *config.SessionCookieConfig, refreshLifeTime time.Duration) *CookieSession {
+	return &CookieSession{
+		server:          server,
+		interceptor:     interceptor,
+		tokens:          tokens,
+		cfg:             cfg,
+		refreshLifeTime: refreshLifeTime,
+	}
+}
+
+func (cs *CookieSession) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST "+cookieSessionPath+"sign-in", cs.signIn)
+	mux.HandleFunc("POST "+cookieSessionPath+"sign-in-with-code", cs.signInWithCode)
+	mux.HandleFunc("POST "+cookieSessionPath+"verify-mfa", cs.verifyMFA)
+	mux.HandleFunc("POST "+cookieSessionPath+"refresh-tokens", cs.refreshTokens)
+	mux.HandleFunc("POST "+cookieSessionPath+"logout", cs.logout)
+}
+
+func (cs *CookieSession) signIn(w http.ResponseWriter, r *http.Request) {
+	body, err := readGatewayBody(w, r)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	req := new(desc.SignInRequest)
+	if err := unmarshalGatewayBody(body, req); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	res, err := cs.call(r, "SignIn", req)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	resp := res.(*desc.SignInResponse)
+
+	cs.writeSignInResponse(w, resp)
+}
+
+func (cs *CookieSession) signInWithCode(w http.ResponseWriter, r *http.Request) {
+	body, err := readGatewayBody(w, r)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	req := new(desc.SignInWithCodeRequest)
+	if err := unmarshalGatewayBody(body, req); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	res, err := cs.call(r, "SignInWithCode", req)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	resp := res.(*desc.SignInResponse)
+
+	cs.writeSignInResponse(w, resp)
+}
+
+// При включенном втором факторе cookie выставляются только после verify-mfa
+func (cs *CookieSession) writeSignInResponse(w http.ResponseWriter, resp *desc.SignInResponse) {
+	if len(resp.MfaChallenge) > 0 {
+		writeGatewayResponse(w, resp)
+		return
+	}
+
+	if err := cs.setSessionCookies(w, resp.Tokens); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	writeGatewayResponse(w, resp)
+}
+
+func (cs *CookieSession) verifyMFA(w http.ResponseWriter, r *http.Request) {
+	body, err := readGatewayBody(w, r)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	req := new(desc.VerifyMFARequest)
+	if err := unmarshalGatewayBody(body, req); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	res, err := cs.call(r, "VerifyMFA", req)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	resp := res.(*desc.VerifyMFAResponse)
+
+	if err := cs.setSessionCookies(w, resp.Tokens); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	writeGatewayResponse(w, resp)
+}
+
+func (cs *CookieSession) refreshTokens(w http.ResponseWriter, r *http.Request) {
+	refresh, err := cs.checkRequest(r)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	res, err := cs.call(r, "RefreshTokens", &desc.RefreshTokensRequest{RefreshToken: refresh})
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	resp := res.(*desc.RefreshTokensResponse)
+
+	if err := cs.setSessionCookies(w, resp.Tokens); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	writeGatewayResponse(w, resp)
+}
+
+// Отзывается сессия refresh токена из cookie, access токен не нужен: он мог уже истечь.
+// Cookie стираются только после отзыва, иначе при ошибке хранилища сессия осталась бы активной без возможности выйти повторно.
+func (cs *CookieSession) logout(w http.ResponseWriter, r *http.Request) {
+	refresh, err := cs.checkRequest(r)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	info := &grpc.UnaryServerInfo{Server: cs.server, FullMethod: desc.Auth_Logout_FullMethodName}
+	_, err = cs.interceptor(gatewayContext(r), refresh, info, func(ctx context.Context, _ any) (any, error) {
+		return nil, cs.revokeSession(ctx, refresh)
+	})
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	cs.clearSessionCookies(w)
+
+	writeGatewayResponse(w, new(emptypb.Empty))
+}
+
+// revokeSession отзывает семейство refresh токена. Недействительный или неизвестный токен отозвать нельзя,
+// и сессии по нему уже нет, поэтому такой выход считается успешным.
+func (cs *CookieSession) revokeSession(ctx context.Context, refresh string) error {
+	claims, err := cs.tokens.ParseTokenClaims(refresh, "refresh")
+	if err != nil {
+		return nil
+	}
+
+	err = cs.tokens.RevokeRefreshTokenByJTI(ctx, claims.JTI)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Failed to revoke session on logout: %v", err)
+		return status.Error(codes.Internal, "Internal server error")
+	}
+
+	return nil
+}
+
+// call выполняет RPC через тот же обработчик и интерцепторы, что и остальной шлюз, включая ограничение частоты запросов
+func (cs *CookieSession) call(r *http.Request, methodName string, req proto.Message) (any, error) {
+	return invokeMethod(gatewayContext(r), cs.server, cs.interceptor, methodName, req)
+}
+
+// checkRequest сверяет CSRF токен из заголовка с cookie и возвращает refresh токен из cookie
+func (cs *CookieSession) checkRequest(r *http.Request) (string, error) {
+	csrfCookie, err := r.Cookie(csrfTokenCookieName)
+	if err != nil || len(csrfCookie.Value) == 0 {
+		return "", csrfTokenMismatch
+	}
+
+	csrfHeader := r.Header.Get(csrfTokenHeaderName)
+	if subtle.ConstantTimeCompare([]byte(csrfHeader), []byte(csrfCookie.Value)) != 1 {
+		return "", csrfTokenMismatch
+	}
+
+	refreshCookie, err := r.Cookie(refreshTokenCookieName)
+	if err != nil || len(refreshCookie.Value) == 0 {
+		return "", status.Error(codes.Unauthenticated, "Refresh token cookie is missing")
+	}
+
+	return refreshCookie.Value, nil
+}
+
+// setSessionCookies переносит refresh токен из ответа в cookie и выдает новый CSRF токен
+func (cs *CookieSession) setSessionCookies(w http.ResponseWriter, tokens *desc.Tokens) error {
+	if tokens == nil {
+		return status.Error(codes.Internal, "Internal server error")
+	}
+
+	csrf, err := generateCSRFToken()
+	if err != nil {
+		return status.Error(codes.Internal, "Internal server error")
+	}
+
+	maxAge := int(cs.refreshLifeTime.Seconds())
+	http.SetCookie(w, cs.cookie(refreshTokenCookieName, tokens.Refresh, cookieSessionPath, true, maxAge))
+	http.SetCookie(w, cs.cookie(csrfTokenCookieName, csrf, "/", false, maxAge))
+
+	tokens.Refresh = ""
+	return nil
+}
+
+func (cs *CookieSession) clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, cs.cookie(refreshTokenCookieName, "", cookieSessionPath, true, -1))
+	http.SetCookie(w, cs.cookie(csrfTokenCookieName, "", "/", false, -1))
+}
+
+// CSRF cookie не HttpOnly: ее значение скрипт клиента копирует в заголовок X-CSRF-Token
+func (cs *CookieSession) cookie(name string, value string, path string, httpOnly bool, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cs.cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cs.cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: cs.cfg.SameSite,
+	}
+}
+
+func generateCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
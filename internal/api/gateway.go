@@ -0,0 +1,186 @@
+package api
+
+import (
+	"AuthService/internal/metrics"
+	desc "AuthService/pkg/api/v1"
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const gatewayMaxBodySize = 1 << 20
+
+// Gateway отдает каждый RPC сервиса Auth как POST /v1/<method-in-kebab-case> с телом в JSON.
+// Запрос проходит через сгенерированный обработчик gRPC, поэтому метрики и интерцепторы те же, что у gRPC.
+// Здесь же обслуживаются OAuth эндпоинты /introspect и /revoke под тем же ограничением частоты.
+type Gateway struct {
+	server      desc.AuthServer
+	interceptor grpc.UnaryServerInterceptor
+	mux         *http.ServeMux
+}
+
+func NewGateway(server desc.AuthServer, interceptor grpc.UnaryServerInterceptor, limiter rateLimiter, cookies *CookieSession, introspection *IntrospectionHandler, revocation *RevocationHandler) *Gateway {
+	g := &Gateway{
+		server:      server,
+		interceptor: interceptor,
+		mux:         http.NewServeMux(),
+	}
+
+	for _, method := range desc.Auth_ServiceDesc.Methods {
+		g.mux.Handle("POST "+GatewayPath(method.MethodName), g.methodHandler(method))
+	}
+	cookies.Register(g.mux)
+	g.mux.Handle("/introspect", rateLimitOAuth(limiter, IntrospectMethodName, introspection))
+	g.mux.Handle("/revoke", rateLimitOAuth(limiter, RevokeMethodName, revocation))
+	g.mux.Handle("GET /openapi.json", NewOpenAPIHandler())
+
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// GatewayPath переводит имя метода в путь: SignUp -> /v1/sign-up, аббревиатуры не разбиваются: VerifyMFA -> /v1/verify-mfa
+func GatewayPath(methodName string) string {
+	runes := []rune(methodName)
+
+	var b strings.Builder
+	b.WriteString("/v1/")
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (g *Gateway) methodHandler(method grpc.MethodDesc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := readGatewayBody(w, r)
+		if err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+
+		dec := func(v any) error {
+			return unmarshalGatewayBody(body, v.(proto.Message))
+		}
+
+		resp, err := method.Handler(g.server, gatewayContext(r), dec, g.interceptor)
+		if err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+
+		writeGatewayResponse(w, resp.(proto.Message))
+	}
+}
+
+var authMethods = func() map[string]grpc.MethodDesc {
+	methods := make(map[string]grpc.MethodDesc, len(desc.Auth_ServiceDesc.Methods))
+	for _, method := range desc.Auth_ServiceDesc.Methods {
+		methods[method.MethodName] = method
+	}
+	return methods
+}()
+
+// invokeMethod вызывает RPC по имени через сгенерированный обработчик, чтобы запрос прошел через интерцепторы
+func invokeMethod(ctx context.Context, server desc.AuthServer, interceptor grpc.UnaryServerInterceptor, methodName string, req proto.Message) (any, error) {
+	method, ok := authMethods[methodName]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "Method %s is not implemented", methodName)
+	}
+
+	dec := func(v any) error {
+		proto.Merge(v.(proto.Message), req)
+		return nil
+	}
+
+	return method.Handler(server, ctx, dec, interceptor)
+}
+
+func readGatewayBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, gatewayMaxBodySize))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Failed to read request body")
+	}
+
+	return body, nil
+}
+
+func unmarshalGatewayBody(body []byte, m proto.Message) error {
+	if len(body) == 0 {
+		return nil
+	}
+	if err := protojson.Unmarshal(body, m); err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid JSON request body")
+	}
+
+	return nil
+}
+
+func writeGatewayResponse(w http.ResponseWriter, m proto.Message) {
+	out, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		log.Printf("Failed to marshal gateway response: %v", err)
+		writeGatewayError(w, status.Error(codes.Internal, "Internal server error"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(out)
+}
+
+// gatewayContext передает в обработчик те же сведения о клиенте, что пришли бы по gRPC
+func gatewayContext(r *http.Request) context.Context {
+	ctx := metadata.NewIncomingContext(r.Context(), metadata.Pairs("user-agent", r.UserAgent()))
+
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+
+	return ctx
+}
+
+func writeGatewayError(w http.ResponseWriter, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.New(codes.Internal, "Internal server error")
+	}
+
+	out, err := protojson.Marshal(st.Proto())
+	if err != nil {
+		out = []byte(`{"code":13,"message":"Internal server error"}`)
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(info.RetryDelay.AsDuration().Seconds())))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(metrics.MapGRPCCodeToHTTPCode(st.Code()))
+	_, _ = w.Write(out)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/entity"
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type tokenIntrospector interface {
+	VerifyTokenClaims(ctx context.Context, tokenString string, expectedType string) (entity.TokenClaims, error)
+}
+
+// token_type возвращается в тех же обозначениях, что и token_type_hint (RFC 7009, раздел 2.1)
+var introspectionTokenTypes = map[string]string{
+	"access":  "access_token",
+	"refresh": "refresh_token",
+}
+
+type introspectionResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+}
+
+// IntrospectionHandler реализует POST /introspect (RFC 7662).
+// Любой недействительный токен описывается одинаково: {"active": false}.
+type IntrospectionHandler struct {
+	tokens  tokenIntrospector
+	clients *config.OAuthClientsConfig
+}
+
+func NewIntrospectionHandler(tokens tokenIntrospector, clients *config.OAuthClientsConfig) *IntrospectionHandler {
+	return &IntrospectionHandler{
+		tokens:  tokens,
+		clients: clients,
+	}
+}
+
+func (h *IntrospectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	if _, ok := authenticateClient(r, h.clients); !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if len(token) == 0 {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	for _, tokenType := range tokenTypesByHint(r.PostFormValue("token_type_hint")) {
+		claims, err := h.tokens.VerifyTokenClaims(r.Context(), token, tokenType)
+		if err != nil {
+			if !isTokenError(err) {
+				log.Printf("Failed to introspect token: %v", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			continue
+		}
+
+		writeJSON(w, http.StatusOK, introspectionResponse{
+			Active:    true,
+			Sub:       strconv.FormatInt(claims.SubjectId, 10),
+			Exp:       unixTime(claims.ExpiresAt),
+			Iat:       unixTime(claims.IssuedAt),
+			Jti:       claims.JTI,
+			TokenType: introspectionTokenTypes[claims.TokenType],
+			Scope:     strings.Join(claims.Roles, " "),
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, introspectionResponse{Active: false})
+}
+
+// Подсказка token_type_hint лишь задает порядок проверки (RFC 7662, раздел 2.1)
+func tokenTypesByHint(hint string) []string {
+	if hint == "refresh_token" {
+		return []string{"refresh", "access"}
+	}
+	return []string{"access", "refresh"}
+}
+
+// Нулевое время дает 0, и поле опускается из ответа
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func isTokenError(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.Unauthenticated
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"AuthService/internal/signing"
+	"encoding/json"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keys *signing.KeyRing
+}
+
+func NewJWKSHandler(keys *signing.KeyRing) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	keys := h.keys.Keys()
+	set := signing.JWKSet{Keys: make([]signing.JWK, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key.JWK())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(set)
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	desc "AuthService/pkg/api/v1"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	openAPIStatusSchema = "Status"
+	openAPIEmptySchema  = "Empty"
+)
+
+// OpenAPIDocument строит описание HTTP шлюза по дескрипторам из api/v1/auth_api.proto
+func OpenAPIDocument() map[string]any {
+	file := desc.File_api_v1_auth_api_proto
+	service := file.Services().ByName("Auth")
+
+	schemas := map[string]any{
+		openAPIEmptySchema: map[string]any{"type": "object"},
+		openAPIStatusSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "integer", "format": "int32"},
+				"message": map[string]any{"type": "string"},
+				"details": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+			},
+		},
+	}
+	paths := make(map[string]any)
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+
+		paths[GatewayPath(string(method.Name()))] = map[string]any{
+			"post": map[string]any{
+				"operationId": string(method.Name()),
+				"requestBody": map[string]any{
+					"required": true,
+					"content":  openAPIJSONContent(openAPIMessageSchema(method.Input(), schemas)),
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "OK",
+						"content":     openAPIJSONContent(openAPIMessageSchema(method.Output(), schemas)),
+					},
+					"default": map[string]any{
+						"description": "Ошибка в формате google.rpc.Status, HTTP код получен из gRPC кода",
+						"content":     openAPIJSONContent(openAPIRef(openAPIStatusSchema)),
+					},
+				},
+			},
+		}
+	}
+
+	openAPICookieSessionPaths(paths)
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   string(service.FullName()),
+			"version": string(file.Package()),
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+// Пути режима cookie описываются вручную: они не отображаются на RPC один к одному
+func openAPICookieSessionPaths(paths map[string]any) {
+	csrfHeader := map[string]any{
+		"name":     csrfTokenHeaderName,
+		"in":       "header",
+		"required": true,
+		"schema":   map[string]any{"type": "string"},
+	}
+
+	operation := func(id string, input string, output string, csrf bool) map[string]any {
+		op := map[string]any{
+			"operationId": id,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content":     openAPIJSONContent(openAPIRef(output)),
+				},
+				"default": map[string]any{
+					"description": "Ошибка в формате google.rpc.Status",
+					"content":     openAPIJSONContent(openAPIRef(openAPIStatusSchema)),
+				},
+			},
+		}
+		if len(input) > 0 {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  openAPIJSONContent(openAPIRef(input)),
+			}
+		}
+		if csrf {
+			op["parameters"] = []any{csrfHeader}
+		}
+		return map[string]any{"post": op}
+	}
+
+	paths[cookieSessionPath+"sign-in"] = operation("CookieSignIn", "SignInRequest", "SignInResponse", false)
+	paths[cookieSessionPath+"sign-in-with-code"] = operation("CookieSignInWithCode", "SignInWithCodeRequest", "SignInResponse", false)
+	paths[cookieSessionPath+"verify-mfa"] = operation("CookieVerifyMFA", "VerifyMFARequest", "VerifyMFAResponse", false)
+	paths[cookieSessionPath+"refresh-tokens"] = operation("CookieRefreshTokens", "", "RefreshTokensResponse", true)
+	paths[cookieSessionPath+"logout"] = operation("CookieLogout", "", openAPIEmptySchema, true)
+}
+
+func openAPIJSONContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+func openAPIRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func openAPIMessageSchema(message protoreflect.MessageDescriptor, schemas map[string]any) map[string]any {
+	switch message.FullName() {
+	case "google.protobuf.Empty":
+		return openAPIRef(openAPIEmptySchema)
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	name := string(message.Name())
+	if _, ok := schemas[name]; ok {
+		return openAPIRef(name)
+	}
+
+	properties := make(map[string]any)
+	schemas[name] = map[string]any{"type": "object", "properties": properties}
+
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+
+		schema := openAPIFieldSchema(field, schemas)
+		if field.IsList() {
+			schema = map[string]any{"type": "array", "items": schema}
+		}
+		properties[field.JSONName()] = schema
+	}
+
+	return openAPIRef(name)
+}
+
+// Типы соответствуют отображению protojson: 64-битные целые передаются строками
+func openAPIFieldSchema(field protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		return map[string]any{"type": "string"}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return openAPIMessageSchema(field.Message(), schemas)
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+type OpenAPIHandler struct {
+	document []byte
+}
+
+func NewOpenAPIHandler() *OpenAPIHandler {
+	document, _ := json.Marshal(OpenAPIDocument())
+	return &OpenAPIHandler{
+		document: document,
+	}
+}
+
+func (h *OpenAPIHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(h.document)
+}
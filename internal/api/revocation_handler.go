@@ -0,0 +1,78 @@
+package api
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/entity"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+type tokenRevoker interface {
+	ParseTokenClaims(tokenString string, expectedType string) (entity.TokenClaims, error)
+	RevokeTokenByJTI(ctx context.Context, jti string) error
+	RevokeRefreshTokenByJTI(ctx context.Context, jti string) error
+}
+
+// RevocationHandler реализует POST /revoke (RFC 7009).
+// На недействительные и неизвестные токены отвечает 200, как требует RFC.
+type RevocationHandler struct {
+	tokens  tokenRevoker
+	clients *config.OAuthClientsConfig
+}
+
+func NewRevocationHandler(tokens tokenRevoker, clients *config.OAuthClientsConfig) *RevocationHandler {
+	return &RevocationHandler{
+		tokens:  tokens,
+		clients: clients,
+	}
+}
+
+func (h *RevocationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	if _, ok := authenticateClient(r, h.clients); !ok {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if len(token) == 0 {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	for _, tokenType := range tokenTypesByHint(r.PostFormValue("token_type_hint")) {
+		claims, err := h.tokens.ParseTokenClaims(token, tokenType)
+		if err != nil {
+			continue
+		}
+
+		if tokenType == "refresh" {
+			err = h.tokens.RevokeRefreshTokenByJTI(r.Context(), claims.JTI)
+		} else {
+			err = h.tokens.RevokeTokenByJTI(r.Context(), claims.JTI)
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to revoke token: %v", err)
+			writeOAuthError(w, http.StatusServiceUnavailable, "temporarily_unavailable")
+			return
+		}
+		break
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+}
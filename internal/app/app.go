@@ -5,9 +5,14 @@ import (
 	"AuthService/internal/metrics"
 	proto "AuthService/pkg/api/v1"
 	"context"
+	"errors"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -16,6 +21,7 @@ import (
 
 type App struct {
 	grpcServer      *grpc.Server
+	gatewayServer   *http.Server
 	ServiceProvider *serviceProvider
 }
 
@@ -23,6 +29,8 @@ const (
 	portName = "GRPC_PORT"
 )
 
+const gatewayShutdownTimeout = 10 * time.Second
+
 func NewApp(ctx context.Context) (*App, error) {
 	a := &App{}
 
@@ -39,6 +47,7 @@ func (a *App) initDeps(ctx context.Context) error {
 		a.initConfig,
 		a.initServiceProvider,
 		a.initGRPC,
+		a.initGateway,
 	}
 
 	for _, f := range inits {
@@ -52,13 +61,80 @@ func (a *App) initDeps(ctx context.Context) error {
 }
 
 func (a *App) RunMetrics() error {
-	return metrics.Listen("0.0.0.0:8002")
+	mux := http.NewServeMux()
+	mux.Handle("/.well-known/jwks.json", a.ServiceProvider.JWKSHandler())
+
+	return metrics.Listen("0.0.0.0:8002", mux)
+}
+
+func (a *App) RunKeysReloader(ctx context.Context) {
+	ring := a.ServiceProvider.KeyRing()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval := a.ServiceProvider.JWTConfig().KeysReloadInterval; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-tick:
+		}
+
+		changed, err := ring.Reload()
+		if err != nil {
+			log.Printf("Failed to reload JWT signing keys: %v", err)
+			continue
+		}
+		if changed {
+			log.Printf("Reloaded JWT signing keys, active key: %s", ring.Active().ID)
+		}
+	}
+}
+
+func (a *App) RunRevocationCache(ctx context.Context) {
+	a.ServiceProvider.RevocationCache().Run(ctx)
+}
+
+func (a *App) RunTokenJanitor(ctx context.Context) {
+	a.ServiceProvider.TokenJanitor().Run(ctx)
 }
 
 func (a *App) Run() error {
 	return a.runGRPC()
 }
 
+func (a *App) RunGateway() error {
+	log.Printf("Starting HTTP gateway on: %v", a.gatewayServer.Addr)
+
+	err := a.gatewayServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (a *App) Stop() {
+	log.Printf("Stopping HTTP gateway")
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+	defer cancel()
+	if err := a.gatewayServer.Shutdown(ctx); err != nil {
+		log.Printf("Failed to stop HTTP gateway: %v", err)
+	}
+
+	log.Printf("Stopping gRPC Server")
+	a.grpcServer.GracefulStop()
+}
+
 func (a *App) initConfig(_ context.Context) error {
 	err := config.Load(".env")
 	if err != nil {
@@ -74,7 +150,10 @@ func (a *App) initServiceProvider(_ context.Context) error {
 }
 
 func (a *App) initGRPC(_ context.Context) error {
-	a.grpcServer = grpc.NewServer(grpc.Creds(insecure.NewCredentials()))
+	a.grpcServer = grpc.NewServer(
+		grpc.Creds(insecure.NewCredentials()),
+		grpc.UnaryInterceptor(a.ServiceProvider.RateLimiter().UnaryServerInterceptor()),
+	)
 
 	reflection.Register(a.grpcServer)
 
@@ -85,6 +164,16 @@ func (a *App) initGRPC(_ context.Context) error {
 	return nil
 }
 
+func (a *App) initGateway(_ context.Context) error {
+	a.gatewayServer = &http.Server{
+		Addr:              a.ServiceProvider.HTTPGatewayConfig().Address(),
+		Handler:           a.ServiceProvider.Gateway(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return nil
+}
+
 func (a *App) runGRPC() error {
 	log.Printf("Starting gRPC Server")
 	port := os.Getenv(portName)
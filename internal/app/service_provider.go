@@ -3,22 +3,71 @@ package app
 import (
 	"AuthService/internal/api"
 	"AuthService/internal/config"
+	"AuthService/internal/dto"
 	"AuthService/internal/external"
+	"AuthService/internal/interceptor"
+	"AuthService/internal/mfa"
 	"AuthService/internal/repository"
 	"AuthService/internal/service"
+	"AuthService/internal/signing"
 	"AuthService/internal/usecase"
+	"AuthService/internal/validation"
+	proto "AuthService/pkg/api/v1"
+	"context"
 	"log"
 
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 type serviceProvider struct {
 	grpcConfig config.GRPCConfig
 
+	httpGatewayConfig config.HTTPGatewayConfig
+
+	gateway *api.Gateway
+
+	sessionCookieConfig *config.SessionCookieConfig
+
+	cookieSession *api.CookieSession
+
+	passwordPolicyConfig *config.PasswordPolicyConfig
+
+	validator *validation.Validator
+
+	loginProtectionConfig *config.LoginProtectionConfig
+
+	loginAttemptsRepository *repository.LoginAttemptsRepository
+
+	loginThrottleService *service.LoginThrottleService
+
+	rateLimitConfig *config.RateLimitConfig
+
+	antiEnumerationConfig *config.AntiEnumerationConfig
+
+	mfaConfig               *config.MFAConfig
+	mfaSecretCipher         *mfa.SecretCipher
+	totpRepository          *repository.TOTPRepository
+	recoveryCodesRepository *repository.RecoveryCodesRepository
+	mfaChallengesRepository *repository.MFAChallengesRepository
+	mfaService              *service.MFAService
+
+	loginCodeConfig      *config.LoginCodeConfig
+	loginCodesRepository *repository.OneTimeCodesRepository
+	loginCodes           *service.OneTimeCodeService
+	loginCodeService     *service.LoginCodeService
+
+	rateLimiter *interceptor.RateLimiter
+
 	credentialsRepository *repository.CredentialsRepository
 
 	tokensRepository *repository.TokensRepository
 
+	confirmationCodesRepository *repository.OneTimeCodesRepository
+	confirmationCodes           *service.OneTimeCodeService
+
+	passwordResetTokensRepository *repository.PasswordResetTokensRepository
+
 	credentialsUseCase *usecase.CredentialsUseCase
 
 	authServerImpl *api.AuthImplementationSever
@@ -27,11 +76,41 @@ type serviceProvider struct {
 
 	tokensService *service.TokensService
 
+	sessionsService *service.SessionsService
+
+	revocationCache *service.RevocationCache
+
+	sessionsRepository *repository.SessionsRepository
+
 	gormDB *gorm.DB
 
 	rabbitMqConfig *config.RabbitMqConfig
 
 	notificationExternal *external.NotificationExternal
+
+	confirmationConfig *config.ConfirmationConfig
+
+	passwordResetConfig *config.PasswordResetConfig
+
+	jwtConfig *config.JWTConfig
+
+	keyRing *signing.KeyRing
+
+	jwksHandler *api.JWKSHandler
+
+	tokenStoreConfig *config.TokenStoreConfig
+
+	redisClient *redis.Client
+
+	tokenPurgeConfig *config.TokenPurgeConfig
+
+	tokenJanitor *service.TokenJanitor
+
+	oauthClientsConfig *config.OAuthClientsConfig
+
+	introspectionHandler *api.IntrospectionHandler
+
+	revocationHandler *api.RevocationHandler
 }
 
 func newServiceProvider() *serviceProvider {
@@ -51,6 +130,207 @@ func (s *serviceProvider) GRPCConfig() config.GRPCConfig {
 	return s.grpcConfig
 }
 
+func (s *serviceProvider) HTTPGatewayConfig() config.HTTPGatewayConfig {
+	if s.httpGatewayConfig == nil {
+		cfg, err := config.NewHTTPGatewayConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize HTTP gateway config: %v", err)
+		}
+
+		s.httpGatewayConfig = cfg
+	}
+
+	return s.httpGatewayConfig
+}
+
+func (s *serviceProvider) SessionCookieConfig() *config.SessionCookieConfig {
+	if s.sessionCookieConfig == nil {
+		cfg, err := config.NewSessionCookieConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize session cookie config: %v", err)
+		}
+
+		s.sessionCookieConfig = cfg
+	}
+
+	return s.sessionCookieConfig
+}
+
+func (s *serviceProvider) PasswordPolicyConfig() *config.PasswordPolicyConfig {
+	if s.passwordPolicyConfig == nil {
+		cfg, err := config.NewPasswordPolicyConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize password policy config: %v", err)
+		}
+
+		s.passwordPolicyConfig = cfg
+	}
+
+	return s.passwordPolicyConfig
+}
+
+func (s *serviceProvider) LoginProtectionConfig() *config.LoginProtectionConfig {
+	if s.loginProtectionConfig == nil {
+		cfg, err := config.NewLoginProtectionConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize login protection config: %v", err)
+		}
+
+		s.loginProtectionConfig = cfg
+	}
+
+	return s.loginProtectionConfig
+}
+
+func (s *serviceProvider) RateLimitConfig() *config.RateLimitConfig {
+	if s.rateLimitConfig == nil {
+		cfg, err := config.NewRateLimitConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize rate limit config: %v", err)
+		}
+
+		methods := []string{api.IntrospectMethodName, api.RevokeMethodName}
+		for _, method := range proto.Auth_ServiceDesc.Methods {
+			methods = append(methods, method.MethodName)
+		}
+		if err := cfg.CheckMethods(methods); err != nil {
+			log.Fatalf("Failed to initialize rate limit config: %v", err)
+		}
+
+		s.rateLimitConfig = cfg
+	}
+
+	return s.rateLimitConfig
+}
+
+func (s *serviceProvider) AntiEnumerationConfig() *config.AntiEnumerationConfig {
+	if s.antiEnumerationConfig == nil {
+		cfg, err := config.NewAntiEnumerationConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize anti enumeration config: %v", err)
+		}
+
+		s.antiEnumerationConfig = cfg
+	}
+
+	return s.antiEnumerationConfig
+}
+
+func (s *serviceProvider) MFAConfig() *config.MFAConfig {
+	if s.mfaConfig == nil {
+		cfg, err := config.NewMFAConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize MFA config: %v", err)
+		}
+
+		s.mfaConfig = cfg
+	}
+
+	return s.mfaConfig
+}
+
+// Без MFA_ENCRYPTION_KEY шифр не создается и подключение TOTP недоступно
+func (s *serviceProvider) MFASecretCipher() *mfa.SecretCipher {
+	if s.mfaSecretCipher == nil && len(s.MFAConfig().EncryptionKey) > 0 {
+		cipher, err := mfa.NewSecretCipher(s.MFAConfig().EncryptionKey)
+		if err != nil {
+			log.Fatalf("Failed to initialize MFA secret cipher: %v", err)
+		}
+
+		s.mfaSecretCipher = cipher
+	}
+
+	return s.mfaSecretCipher
+}
+
+func (s *serviceProvider) TOTPRepository() *repository.TOTPRepository {
+	if s.totpRepository == nil {
+		s.totpRepository = repository.NewTOTPRepository()
+	}
+
+	return s.totpRepository
+}
+
+func (s *serviceProvider) RecoveryCodesRepository() *repository.RecoveryCodesRepository {
+	if s.recoveryCodesRepository == nil {
+		s.recoveryCodesRepository = repository.NewRecoveryCodesRepository()
+	}
+
+	return s.recoveryCodesRepository
+}
+
+func (s *serviceProvider) MFAChallengesRepository() *repository.MFAChallengesRepository {
+	if s.mfaChallengesRepository == nil {
+		s.mfaChallengesRepository = repository.NewMFAChallengesRepository()
+	}
+
+	return s.mfaChallengesRepository
+}
+
+func (s *serviceProvider) MFAService() *service.MFAService {
+	if s.mfaService == nil {
+		s.mfaService = service.NewMFAService(s.GormDB(), s.TOTPRepository(), s.RecoveryCodesRepository(), s.MFAChallengesRepository(), s.MFASecretCipher(), s.MFAConfig())
+		if err := s.mfaService.CheckConfigured(context.Background()); err != nil {
+			log.Fatalf("Failed to initialize MFA service: %v", err)
+		}
+	}
+
+	return s.mfaService
+}
+
+func (s *serviceProvider) LoginCodeConfig() *config.LoginCodeConfig {
+	if s.loginCodeConfig == nil {
+		cfg, err := config.NewLoginCodeConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize login code config: %v", err)
+		}
+
+		s.loginCodeConfig = cfg
+	}
+
+	return s.loginCodeConfig
+}
+
+func (s *serviceProvider) LoginCodesRepository() *repository.OneTimeCodesRepository {
+	if s.loginCodesRepository == nil {
+		s.loginCodesRepository = repository.NewOneTimeCodesRepository(dto.LoginCodeTable)
+	}
+
+	return s.loginCodesRepository
+}
+
+func (s *serviceProvider) LoginCodes() *service.OneTimeCodeService {
+	if s.loginCodes == nil {
+		s.loginCodes = service.NewOneTimeCodeService(s.LoginCodesRepository(), &s.LoginCodeConfig().OneTimeCodeConfig)
+	}
+
+	return s.loginCodes
+}
+
+func (s *serviceProvider) LoginCodeService() *service.LoginCodeService {
+	if s.loginCodeService == nil {
+		s.loginCodeService = service.NewLoginCodeService(s.GormDB(), s.CredentialsRepository(), s.LoginCodes(), s.NotificationExternal(), s.LoginCodeConfig())
+	}
+
+	return s.loginCodeService
+}
+
+func (s *serviceProvider) RateLimiter() *interceptor.RateLimiter {
+	if s.rateLimiter == nil {
+		s.rateLimiter = interceptor.NewRateLimiter(s.RateLimitConfig(), interceptor.SystemClock())
+	}
+
+	return s.rateLimiter
+}
+
+func (s *serviceProvider) Validator() *validation.Validator {
+	if s.validator == nil {
+		s.validator = validation.NewValidator(s.PasswordPolicyConfig())
+	}
+
+	return s.validator
+}
+
 func (s *serviceProvider) NotificationExternal() *external.NotificationExternal {
 	if s.notificationExternal == nil {
 		s.notificationExternal = external.NewNotificationExternal(s.RabbitMqConfig())
@@ -73,6 +353,110 @@ func (s *serviceProvider) RabbitMqConfig() *config.RabbitMqConfig {
 	return s.rabbitMqConfig
 }
 
+func (s *serviceProvider) ConfirmationConfig() *config.ConfirmationConfig {
+	if s.confirmationConfig == nil {
+		cfg, err := config.NewConfirmationConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize confirmation config: %v", err)
+		}
+
+		s.confirmationConfig = cfg
+	}
+
+	return s.confirmationConfig
+}
+
+func (s *serviceProvider) PasswordResetConfig() *config.PasswordResetConfig {
+	if s.passwordResetConfig == nil {
+		cfg, err := config.NewPasswordResetConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize password reset config: %v", err)
+		}
+
+		s.passwordResetConfig = cfg
+	}
+
+	return s.passwordResetConfig
+}
+
+func (s *serviceProvider) JWTConfig() *config.JWTConfig {
+	if s.jwtConfig == nil {
+		cfg, err := config.NewJWTConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize JWT config: %v", err)
+		}
+
+		s.jwtConfig = cfg
+	}
+
+	return s.jwtConfig
+}
+
+func (s *serviceProvider) TokenStoreConfig() *config.TokenStoreConfig {
+	if s.tokenStoreConfig == nil {
+		cfg, err := config.NewTokenStoreConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize token store config: %v", err)
+		}
+
+		s.tokenStoreConfig = cfg
+	}
+
+	return s.tokenStoreConfig
+}
+
+func (s *serviceProvider) TokenPurgeConfig() *config.TokenPurgeConfig {
+	if s.tokenPurgeConfig == nil {
+		cfg, err := config.NewTokenPurgeConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize token purge config: %v", err)
+		}
+
+		s.tokenPurgeConfig = cfg
+	}
+
+	return s.tokenPurgeConfig
+}
+
+func (s *serviceProvider) OAuthClientsConfig() *config.OAuthClientsConfig {
+	if s.oauthClientsConfig == nil {
+		cfg, err := config.NewOAuthClientsConfig()
+		if err != nil {
+			log.Fatalf("Failed to initialize OAuth clients config: %v", err)
+		}
+
+		s.oauthClientsConfig = cfg
+	}
+
+	return s.oauthClientsConfig
+}
+
+func (s *serviceProvider) RedisClient() *redis.Client {
+	if s.redisClient == nil {
+		cfg := s.TokenStoreConfig()
+		s.redisClient = redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+	}
+
+	return s.redisClient
+}
+
+func (s *serviceProvider) KeyRing() *signing.KeyRing {
+	if s.keyRing == nil {
+		ring, err := signing.NewKeyRing(s.JWTConfig().KeysDir, s.JWTConfig().AllowedAlgorithms)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+
+		s.keyRing = ring
+	}
+
+	return s.keyRing
+}
+
 func (s *serviceProvider) CredentialsRepository() *repository.CredentialsRepository {
 	if s.credentialsRepository == nil {
 		s.credentialsRepository = repository.NewCredentialsRepository()
@@ -83,7 +467,7 @@ func (s *serviceProvider) CredentialsRepository() *repository.CredentialsReposit
 
 func (s *serviceProvider) CredentialsUseCase() *usecase.CredentialsUseCase {
 	if s.credentialsUseCase == nil {
-		s.credentialsUseCase = usecase.NewCredentialsUseCase(s.CredentialsService(), s.TokensService())
+		s.credentialsUseCase = usecase.NewCredentialsUseCase(s.CredentialsService(), s.TokensService(), s.SessionsService(), s.LoginThrottleService(), s.MFAService(), s.LoginCodeService(), s.Validator(), s.AntiEnumerationConfig())
 	}
 
 	return s.credentialsUseCase
@@ -91,7 +475,7 @@ func (s *serviceProvider) CredentialsUseCase() *usecase.CredentialsUseCase {
 
 func (s *serviceProvider) CredentialsService() *service.CredentialsService {
 	if s.credentialsService == nil {
-		s.credentialsService = service.NewCredentialsService(s.GormDB(), s.CredentialsRepository(), s.TokensRepository(), s.NotificationExternal())
+		s.credentialsService = service.NewCredentialsService(s.GormDB(), s.CredentialsRepository(), s.TokensRepository(), s.ConfirmationCodes(), s.PasswordResetTokensRepository(), s.NotificationExternal(), s.PasswordResetConfig())
 	}
 
 	return s.credentialsService
@@ -99,12 +483,45 @@ func (s *serviceProvider) CredentialsService() *service.CredentialsService {
 
 func (s *serviceProvider) TokensService() *service.TokensService {
 	if s.tokensService == nil {
-		s.tokensService = service.NewTokensService(s.GormDB(), s.CredentialsRepository(), s.TokensRepository())
+		s.tokensService = service.NewTokensService(s.GormDB(), s.CredentialsRepository(), s.TokensRepository(), s.SessionsRepository(), s.KeyRing(), s.RevocationCache(), s.JWTConfig())
 	}
 
 	return s.tokensService
 }
 
+func (s *serviceProvider) RevocationCache() *service.RevocationCache {
+	if s.revocationCache == nil {
+		cfg := s.JWTConfig()
+		s.revocationCache = service.NewRevocationCache(s.GormDB(), s.TokensRepository(), cfg.AccessLifeTime+cfg.ClockSkew, cfg.RevocationSyncInterval)
+	}
+
+	return s.revocationCache
+}
+
+func (s *serviceProvider) TokenJanitor() *service.TokenJanitor {
+	if s.tokenJanitor == nil {
+		s.tokenJanitor = service.NewTokenJanitor(s.GormDB(), s.TokensRepository(), s.SessionsRepository(), s.LoginAttemptsRepository(), s.TokenPurgeConfig(), s.LoginProtectionConfig(), s.JWTConfig().RefreshLifeTime)
+	}
+
+	return s.tokenJanitor
+}
+
+func (s *serviceProvider) LoginThrottleService() *service.LoginThrottleService {
+	if s.loginThrottleService == nil {
+		s.loginThrottleService = service.NewLoginThrottleService(s.GormDB(), s.LoginAttemptsRepository(), s.LoginProtectionConfig())
+	}
+
+	return s.loginThrottleService
+}
+
+func (s *serviceProvider) SessionsService() *service.SessionsService {
+	if s.sessionsService == nil {
+		s.sessionsService = service.NewSessionsService(s.GormDB(), s.SessionsRepository(), s.TokensRepository())
+	}
+
+	return s.sessionsService
+}
+
 func (s *serviceProvider) GormDB() *gorm.DB {
 	if s.gormDB == nil {
 		s.gormDB = config.NewDatabase()
@@ -121,11 +538,98 @@ func (s *serviceProvider) AuthServerImpl() *api.AuthImplementationSever {
 	return s.authServerImpl
 }
 
+func (s *serviceProvider) Gateway() *api.Gateway {
+	if s.gateway == nil {
+		s.gateway = api.NewGateway(s.AuthServerImpl(), s.RateLimiter().UnaryServerInterceptor(), s.RateLimiter(), s.CookieSession(), s.IntrospectionHandler(), s.RevocationHandler())
+	}
+
+	return s.gateway
+}
+
+func (s *serviceProvider) CookieSession() *api.CookieSession {
+	if s.cookieSession == nil {
+		s.cookieSession = api.NewCookieSession(s.AuthServerImpl(), s.RateLimiter().UnaryServerInterceptor(), s.TokensService(), s.SessionCookieConfig(), s.JWTConfig().RefreshLifeTime)
+	}
+
+	return s.cookieSession
+}
+
+func (s *serviceProvider) JWKSHandler() *api.JWKSHandler {
+	if s.jwksHandler == nil {
+		s.jwksHandler = api.NewJWKSHandler(s.KeyRing())
+	}
+
+	return s.jwksHandler
+}
+
+func (s *serviceProvider) IntrospectionHandler() *api.IntrospectionHandler {
+	if s.introspectionHandler == nil {
+		s.introspectionHandler = api.NewIntrospectionHandler(s.TokensService(), s.OAuthClientsConfig())
+	}
+
+	return s.introspectionHandler
+}
+
+func (s *serviceProvider) RevocationHandler() *api.RevocationHandler {
+	if s.revocationHandler == nil {
+		s.revocationHandler = api.NewRevocationHandler(s.TokensService(), s.OAuthClientsConfig())
+	}
+
+	return s.revocationHandler
+}
+
 func (s *serviceProvider) TokensRepository() *repository.TokensRepository {
 	if s.tokensRepository == nil {
-		s.tokensRepository = repository.NewTokensRepository()
+		var store repository.TokenStore
+		switch s.TokenStoreConfig().Backend {
+		case config.TokenStoreRedis:
+			store = repository.NewRedisTokenStore(s.RedisClient(), s.JWTConfig().RefreshLifeTime)
+		default:
+			store = repository.NewPostgresTokenStore()
+		}
+
+		s.tokensRepository = repository.NewTokensRepository(store)
 	}
 
 	return s.tokensRepository
 }
 
+func (s *serviceProvider) ConfirmationCodesRepository() *repository.OneTimeCodesRepository {
+	if s.confirmationCodesRepository == nil {
+		s.confirmationCodesRepository = repository.NewOneTimeCodesRepository(dto.ConfirmationCodeTable)
+	}
+
+	return s.confirmationCodesRepository
+}
+
+func (s *serviceProvider) ConfirmationCodes() *service.OneTimeCodeService {
+	if s.confirmationCodes == nil {
+		s.confirmationCodes = service.NewOneTimeCodeService(s.ConfirmationCodesRepository(), &s.ConfirmationConfig().OneTimeCodeConfig)
+	}
+
+	return s.confirmationCodes
+}
+
+func (s *serviceProvider) PasswordResetTokensRepository() *repository.PasswordResetTokensRepository {
+	if s.passwordResetTokensRepository == nil {
+		s.passwordResetTokensRepository = repository.NewPasswordResetTokensRepository()
+	}
+
+	return s.passwordResetTokensRepository
+}
+
+func (s *serviceProvider) LoginAttemptsRepository() *repository.LoginAttemptsRepository {
+	if s.loginAttemptsRepository == nil {
+		s.loginAttemptsRepository = repository.NewLoginAttemptsRepository()
+	}
+
+	return s.loginAttemptsRepository
+}
+
+func (s *serviceProvider) SessionsRepository() *repository.SessionsRepository {
+	if s.sessionsRepository == nil {
+		s.sessionsRepository = repository.NewSessionsRepository()
+	}
+
+	return s.sessionsRepository
+}
@@ -0,0 +1,21 @@
+package config
+
+const antiEnumerationEnabledName = "ANTI_ENUMERATION_ENABLED"
+
+const defaultAntiEnumerationEnabled = true
+
+type AntiEnumerationConfig struct {
+	// Если включено, SignIn и SignUp не раскрывают, зарегистрирована ли почта
+	Enabled bool
+}
+
+func NewAntiEnumerationConfig() (*AntiEnumerationConfig, error) {
+	enabled, err := getEnvBool(antiEnumerationEnabledName, defaultAntiEnumerationEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AntiEnumerationConfig{
+		Enabled: enabled,
+	}, nil
+}
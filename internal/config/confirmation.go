@@ -0,0 +1,37 @@
+package config
+
+const (
+	confirmationCodeLifeTimeName     = "CONFIRMATION_CODE_LIFE_TIME_MINUTE"
+	confirmationCodeMaxAttemptsName  = "CONFIRMATION_CODE_MAX_ATTEMPTS"
+	confirmationResendCooldownName   = "CONFIRMATION_RESEND_COOLDOWN_SECOND"
+	confirmationResendDailyLimitName = "CONFIRMATION_RESEND_DAILY_LIMIT"
+)
+
+const (
+	defaultConfirmationCodeLifeTime     = 15
+	defaultConfirmationCodeMaxAttempts  = 5
+	defaultConfirmationResendCooldown   = 60
+	defaultConfirmationResendDailyLimit = 5
+)
+
+type ConfirmationConfig struct {
+	OneTimeCodeConfig
+}
+
+func NewConfirmationConfig() (*ConfirmationConfig, error) {
+	cfg, err := newOneTimeCodeConfig(oneTimeCodeEnv{
+		lifeTimeName:          confirmationCodeLifeTimeName,
+		maxAttemptsName:       confirmationCodeMaxAttemptsName,
+		resendCooldownName:    confirmationResendCooldownName,
+		dailyLimitName:        confirmationResendDailyLimitName,
+		defaultLifeTime:       defaultConfirmationCodeLifeTime,
+		defaultMaxAttempts:    defaultConfirmationCodeMaxAttempts,
+		defaultResendCooldown: defaultConfirmationResendCooldown,
+		defaultDailyLimit:     defaultConfirmationResendDailyLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfirmationConfig{OneTimeCodeConfig: cfg}, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"net"
+)
+
+const (
+	httpGatewayHostName = "HTTP_GATEWAY_HOST"
+	httpGatewayPortName = "HTTP_GATEWAY_PORT"
+)
+
+const (
+	defaultHTTPGatewayHost = "0.0.0.0"
+	defaultHTTPGatewayPort = "8001"
+)
+
+type HTTPGatewayConfig interface {
+	Address() string
+}
+
+type httpGatewayConfig struct {
+	host string
+	port string
+}
+
+func (cfg *httpGatewayConfig) Address() string {
+	return net.JoinHostPort(cfg.host, cfg.port)
+}
+
+func NewHTTPGatewayConfig() (HTTPGatewayConfig, error) {
+	return &httpGatewayConfig{
+		host: getEnvString(httpGatewayHostName, defaultHTTPGatewayHost),
+		port: getEnvString(httpGatewayPortName, defaultHTTPGatewayPort),
+	}, nil
+}
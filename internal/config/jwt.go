@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	jwtKeysDirName            = "JWT_KEYS_DIR"
+	jwtKeysReloadIntervalName = "JWT_KEYS_RELOAD_INTERVAL_SECOND"
+	jwtIssuerName             = "JWT_ISSUER"
+	jwtAudienceName           = "JWT_AUDIENCE"
+	jwtAllowedAlgorithmsName  = "JWT_ALLOWED_ALGORITHMS"
+	jwtClockSkewName          = "JWT_CLOCK_SKEW_SECOND"
+	jwtRefreshLifeTimeName    = "JWT_REFRESH_LIFE_TIME_DAY"
+	jwtAccessLifeTimeName     = "JWT_ACCESS_LIFE_TIME_MINUTE"
+	jwtRevocationSyncName     = "JWT_REVOCATION_SYNC_INTERVAL_SECOND"
+)
+
+const (
+	defaultJWTKeysReloadInterval = 60
+	defaultJWTIssuer             = "auth-service"
+	defaultJWTAudience           = "auth-service"
+	defaultJWTAllowedAlgorithms  = "EdDSA,RS256"
+	defaultJWTClockSkew          = 30
+	defaultJWTRevocationSync     = 5
+)
+
+var supportedJWTAlgorithms = []string{"EdDSA", "RS256"}
+
+type JWTConfig struct {
+	KeysDir string
+	// Период проверки каталога ключей, 0 - только по SIGHUP
+	KeysReloadInterval time.Duration
+
+	Issuer            string
+	Audience          string
+	AllowedAlgorithms []string
+	ClockSkew         time.Duration
+
+	AccessLifeTime  time.Duration
+	RefreshLifeTime time.Duration
+
+	// Период синхронизации кэша отозванных access токенов
+	RevocationSyncInterval time.Duration
+}
+
+func NewJWTConfig() (*JWTConfig, error) {
+	dir := os.Getenv(jwtKeysDirName)
+	if len(dir) == 0 {
+		return nil, errors.New("environment variable JWT_KEYS_DIR is not set")
+	}
+
+	reloadInterval, err := getEnvInt(jwtKeysReloadIntervalName, defaultJWTKeysReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+	if reloadInterval < 0 {
+		return nil, errors.New("environment variable JWT_KEYS_RELOAD_INTERVAL_SECOND must not be negative")
+	}
+
+	clockSkew, err := getEnvInt(jwtClockSkewName, defaultJWTClockSkew)
+	if err != nil {
+		return nil, err
+	}
+	if clockSkew < 0 {
+		return nil, errors.New("environment variable JWT_CLOCK_SKEW_SECOND must not be negative")
+	}
+
+	accessLifeTime, err := getEnvInt(jwtAccessLifeTimeName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if accessLifeTime < 1 {
+		return nil, errors.New("environment variable JWT_ACCESS_LIFE_TIME_MINUTE must be positive")
+	}
+
+	refreshLifeTime, err := getEnvInt(jwtRefreshLifeTimeName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if refreshLifeTime < 1 {
+		return nil, errors.New("environment variable JWT_REFRESH_LIFE_TIME_DAY must be positive")
+	}
+
+	revocationSync, err := getEnvInt(jwtRevocationSyncName, defaultJWTRevocationSync)
+	if err != nil {
+		return nil, err
+	}
+	if revocationSync < 1 {
+		return nil, errors.New("environment variable JWT_REVOCATION_SYNC_INTERVAL_SECOND must be positive")
+	}
+
+	algorithms := strings.Split(getEnvString(jwtAllowedAlgorithmsName, defaultJWTAllowedAlgorithms), ",")
+	for i, alg := range algorithms {
+		algorithms[i] = strings.TrimSpace(alg)
+		if !slices.Contains(supportedJWTAlgorithms, algorithms[i]) {
+			return nil, fmt.Errorf("environment variable JWT_ALLOWED_ALGORITHMS contains unsupported algorithm %q", algorithms[i])
+		}
+	}
+
+	return &JWTConfig{
+		KeysDir:            dir,
+		KeysReloadInterval: time.Duration(reloadInterval) * time.Second,
+		Issuer:             getEnvString(jwtIssuerName, defaultJWTIssuer),
+		Audience:           getEnvString(jwtAudienceName, defaultJWTAudience),
+		AllowedAlgorithms:  algorithms,
+		ClockSkew:          time.Duration(clockSkew) * time.Second,
+
+		AccessLifeTime:  time.Duration(accessLifeTime) * time.Minute,
+		RefreshLifeTime: time.Duration(refreshLifeTime) * 24 * time.Hour,
+
+		RevocationSyncInterval: time.Duration(revocationSync) * time.Second,
+	}, nil
+}
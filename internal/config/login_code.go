@@ -0,0 +1,37 @@
+package config
+
+const (
+	loginCodeLifeTimeName       = "LOGIN_CODE_LIFE_TIME_MINUTE"
+	loginCodeMaxAttemptsName    = "LOGIN_CODE_MAX_ATTEMPTS"
+	loginCodeResendCooldownName = "LOGIN_CODE_RESEND_COOLDOWN_SECOND"
+	loginCodeDailyLimitName     = "LOGIN_CODE_DAILY_LIMIT"
+)
+
+const (
+	defaultLoginCodeLifeTime       = 10
+	defaultLoginCodeMaxAttempts    = 5
+	defaultLoginCodeResendCooldown = 60
+	defaultLoginCodeDailyLimit     = 10
+)
+
+type LoginCodeConfig struct {
+	OneTimeCodeConfig
+}
+
+func NewLoginCodeConfig() (*LoginCodeConfig, error) {
+	cfg, err := newOneTimeCodeConfig(oneTimeCodeEnv{
+		lifeTimeName:          loginCodeLifeTimeName,
+		maxAttemptsName:       loginCodeMaxAttemptsName,
+		resendCooldownName:    loginCodeResendCooldownName,
+		dailyLimitName:        loginCodeDailyLimitName,
+		defaultLifeTime:       defaultLoginCodeLifeTime,
+		defaultMaxAttempts:    defaultLoginCodeMaxAttempts,
+		defaultResendCooldown: defaultLoginCodeResendCooldown,
+		defaultDailyLimit:     defaultLoginCodeDailyLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginCodeConfig{OneTimeCodeConfig: cfg}, nil
+}
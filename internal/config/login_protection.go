@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	loginBackoffThresholdName   = "LOGIN_BACKOFF_THRESHOLD"
+	loginBackoffBaseName        = "LOGIN_BACKOFF_BASE_SECOND"
+	loginLockoutThresholdName   = "LOGIN_LOCKOUT_THRESHOLD"
+	loginIPLockoutThresholdName = "LOGIN_IP_LOCKOUT_THRESHOLD"
+	loginLockoutDurationName    = "LOGIN_LOCKOUT_DURATION_MINUTE"
+	loginFailureWindowName      = "LOGIN_FAILURE_WINDOW_MINUTE"
+)
+
+const (
+	defaultLoginBackoffThreshold   = 3
+	defaultLoginBackoffBase        = 1
+	defaultLoginLockoutThreshold   = 10
+	defaultLoginIPLockoutThreshold = 50
+	defaultLoginLockoutDuration    = 15
+	defaultLoginFailureWindow      = 60
+)
+
+type LoginProtectionConfig struct {
+	// С какой неудачной попытки подряд включается экспоненциальная задержка
+	BackoffThreshold int
+	BackoffBase      time.Duration
+	// Порог блокировки для аккаунта и для IP адреса клиента
+	LockoutThreshold   int
+	IPLockoutThreshold int
+	LockoutDuration    time.Duration
+	// Через сколько после последней неудачи счетчик начинается заново
+	FailureWindow time.Duration
+}
+
+func NewLoginProtectionConfig() (*LoginProtectionConfig, error) {
+	backoffThreshold, err := getEnvInt(loginBackoffThresholdName, defaultLoginBackoffThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if backoffThreshold < 1 {
+		return nil, errors.New("environment variable LOGIN_BACKOFF_THRESHOLD must be positive")
+	}
+
+	backoffBase, err := getEnvInt(loginBackoffBaseName, defaultLoginBackoffBase)
+	if err != nil {
+		return nil, err
+	}
+	if backoffBase < 1 {
+		return nil, errors.New("environment variable LOGIN_BACKOFF_BASE_SECOND must be positive")
+	}
+
+	lockoutThreshold, err := getEnvInt(loginLockoutThresholdName, defaultLoginLockoutThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if lockoutThreshold < backoffThreshold {
+		return nil, errors.New("environment variable LOGIN_LOCKOUT_THRESHOLD must not be less than LOGIN_BACKOFF_THRESHOLD")
+	}
+
+	ipLockoutThreshold, err := getEnvInt(loginIPLockoutThresholdName, defaultLoginIPLockoutThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if ipLockoutThreshold < 1 {
+		return nil, errors.New("environment variable LOGIN_IP_LOCKOUT_THRESHOLD must be positive")
+	}
+
+	lockoutDuration, err := getEnvInt(loginLockoutDurationName, defaultLoginLockoutDuration)
+	if err != nil {
+		return nil, err
+	}
+	if lockoutDuration < 1 {
+		return nil, errors.New("environment variable LOGIN_LOCKOUT_DURATION_MINUTE must be positive")
+	}
+
+	failureWindow, err := getEnvInt(loginFailureWindowName, defaultLoginFailureWindow)
+	if err != nil {
+		return nil, err
+	}
+	if failureWindow < 1 {
+		return nil, errors.New("environment variable LOGIN_FAILURE_WINDOW_MINUTE must be positive")
+	}
+
+	return &LoginProtectionConfig{
+		BackoffThreshold:   backoffThreshold,
+		BackoffBase:        time.Duration(backoffBase) * time.Second,
+		LockoutThreshold:   lockoutThreshold,
+		IPLockoutThreshold: ipLockoutThreshold,
+		LockoutDuration:    time.Duration(lockoutDuration) * time.Minute,
+		FailureWindow:      time.Duration(failureWindow) * time.Minute,
+	}, nil
+}
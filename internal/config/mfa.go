@@ -0,0 +1,79 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	mfaEncryptionKeyName        = "MFA_ENCRYPTION_KEY"
+	mfaTOTPIssuerName           = "MFA_TOTP_ISSUER"
+	mfaChallengeLifeTimeName    = "MFA_CHALLENGE_LIFE_TIME_MINUTE"
+	mfaChallengeMaxAttemptsName = "MFA_CHALLENGE_MAX_ATTEMPTS"
+	mfaRecoveryCodesCountName   = "MFA_RECOVERY_CODES_COUNT"
+)
+
+const (
+	defaultMFATOTPIssuer           = "AuthService"
+	defaultMFAChallengeLifeTime    = 5
+	defaultMFAChallengeMaxAttempts = 5
+	defaultMFARecoveryCodesCount   = 10
+)
+
+type MFAConfig struct {
+	// Ключ AES-256 в base64; без него подключить TOTP нельзя, а при уже подключенном TOTP сервис не запустится
+	EncryptionKey []byte
+	TOTPIssuer    string
+
+	ChallengeLifeTime    time.Duration
+	ChallengeMaxAttempts int
+	RecoveryCodesCount   int
+}
+
+func NewMFAConfig() (*MFAConfig, error) {
+	var key []byte
+	if value := os.Getenv(mfaEncryptionKeyName); len(value) > 0 {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, errors.New("environment variable MFA_ENCRYPTION_KEY is not valid base64")
+		}
+		if len(decoded) != 32 {
+			return nil, errors.New("environment variable MFA_ENCRYPTION_KEY must decode to 32 bytes")
+		}
+		key = decoded
+	}
+
+	lifeTime, err := getEnvInt(mfaChallengeLifeTimeName, defaultMFAChallengeLifeTime)
+	if err != nil {
+		return nil, err
+	}
+	if lifeTime < 1 {
+		return nil, errors.New("environment variable MFA_CHALLENGE_LIFE_TIME_MINUTE must be positive")
+	}
+
+	maxAttempts, err := getEnvInt(mfaChallengeMaxAttemptsName, defaultMFAChallengeMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if maxAttempts < 1 {
+		return nil, errors.New("environment variable MFA_CHALLENGE_MAX_ATTEMPTS must be positive")
+	}
+
+	recoveryCodesCount, err := getEnvInt(mfaRecoveryCodesCountName, defaultMFARecoveryCodesCount)
+	if err != nil {
+		return nil, err
+	}
+	if recoveryCodesCount < 1 {
+		return nil, errors.New("environment variable MFA_RECOVERY_CODES_COUNT must be positive")
+	}
+
+	return &MFAConfig{
+		EncryptionKey:        key,
+		TOTPIssuer:           getEnvString(mfaTOTPIssuerName, defaultMFATOTPIssuer),
+		ChallengeLifeTime:    time.Duration(lifeTime) * time.Minute,
+		ChallengeMaxAttempts: maxAttempts,
+		RecoveryCodesCount:   recoveryCodesCount,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	oauthClientsName = "OAUTH_CLIENTS"
+)
+
+// OAuthClientsConfig - клиенты HTTP эндпоинтов introspect/revoke, заданные как "id:secret,id:secret"
+type OAuthClientsConfig struct {
+	Clients map[string]string
+}
+
+func NewOAuthClientsConfig() (*OAuthClientsConfig, error) {
+	clients := make(map[string]string)
+
+	value := getEnvString(oauthClientsName, "")
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || len(id) == 0 || len(secret) == 0 {
+			return nil, fmt.Errorf("environment variable OAUTH_CLIENTS has invalid client entry %q", id)
+		}
+		clients[id] = secret
+	}
+
+	return &OAuthClientsConfig{
+		Clients: clients,
+	}, nil
+}
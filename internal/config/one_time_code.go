@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// OneTimeCodeConfig - параметры кодов из письма. Коды подтверждения почты и входа без пароля
+// настраиваются одинаково, но каждый своими переменными окружения.
+type OneTimeCodeConfig struct {
+	CodeLifeTime   time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+	// Сколько кодов можно выдать пользователю за сутки
+	DailyLimit int
+}
+
+// oneTimeCodeEnv - имена переменных окружения и значения по умолчанию для одного вида кодов
+type oneTimeCodeEnv struct {
+	lifeTimeName       string
+	maxAttemptsName    string
+	resendCooldownName string
+	dailyLimitName     string
+
+	defaultLifeTime       int
+	defaultMaxAttempts    int
+	defaultResendCooldown int
+	defaultDailyLimit     int
+}
+
+func newOneTimeCodeConfig(env oneTimeCodeEnv) (OneTimeCodeConfig, error) {
+	lifeTime, err := getEnvInt(env.lifeTimeName, env.defaultLifeTime)
+	if err != nil {
+		return OneTimeCodeConfig{}, err
+	}
+	if lifeTime < 1 {
+		return OneTimeCodeConfig{}, fmt.Errorf("environment variable %s must be positive", env.lifeTimeName)
+	}
+
+	maxAttempts, err := getEnvInt(env.maxAttemptsName, env.defaultMaxAttempts)
+	if err != nil {
+		return OneTimeCodeConfig{}, err
+	}
+	if maxAttempts < 1 {
+		return OneTimeCodeConfig{}, fmt.Errorf("environment variable %s must be positive", env.maxAttemptsName)
+	}
+
+	resendCooldown, err := getEnvInt(env.resendCooldownName, env.defaultResendCooldown)
+	if err != nil {
+		return OneTimeCodeConfig{}, err
+	}
+	if resendCooldown < 0 {
+		return OneTimeCodeConfig{}, fmt.Errorf("environment variable %s must not be negative", env.resendCooldownName)
+	}
+
+	dailyLimit, err := getEnvInt(env.dailyLimitName, env.defaultDailyLimit)
+	if err != nil {
+		return OneTimeCodeConfig{}, err
+	}
+	if dailyLimit < 1 {
+		return OneTimeCodeConfig{}, fmt.Errorf("environment variable %s must be positive", env.dailyLimitName)
+	}
+
+	return OneTimeCodeConfig{
+		CodeLifeTime:   time.Duration(lifeTime) * time.Minute,
+		MaxAttempts:    maxAttempts,
+		ResendCooldown: time.Duration(resendCooldown) * time.Second,
+		DailyLimit:     dailyLimit,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+)
+
+const (
+	passwordMinLengthName      = "PASSWORD_MIN_LENGTH"
+	passwordMaxLengthName      = "PASSWORD_MAX_LENGTH"
+	passwordRequireLowerName   = "PASSWORD_REQUIRE_LOWER"
+	passwordRequireUpperName   = "PASSWORD_REQUIRE_UPPER"
+	passwordRequireDigitName   = "PASSWORD_REQUIRE_DIGIT"
+	passwordRequireSpecialName = "PASSWORD_REQUIRE_SPECIAL"
+)
+
+// bcrypt учитывает только первые 72 байта пароля
+const BcryptMaxPasswordLength = 72
+
+const (
+	defaultPasswordMinLength      = 8
+	defaultPasswordMaxLength      = BcryptMaxPasswordLength
+	defaultPasswordRequireLower   = true
+	defaultPasswordRequireUpper   = true
+	defaultPasswordRequireDigit   = true
+	defaultPasswordRequireSpecial = false
+)
+
+type PasswordPolicyConfig struct {
+	MinLength int
+	// Максимальная длина в байтах
+	MaxLength int
+
+	RequireLower   bool
+	RequireUpper   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+func NewPasswordPolicyConfig() (*PasswordPolicyConfig, error) {
+	minLength, err := getEnvInt(passwordMinLengthName, defaultPasswordMinLength)
+	if err != nil {
+		return nil, err
+	}
+	if minLength < 1 {
+		return nil, errors.New("environment variable PASSWORD_MIN_LENGTH must be positive")
+	}
+
+	maxLength, err := getEnvInt(passwordMaxLengthName, defaultPasswordMaxLength)
+	if err != nil {
+		return nil, err
+	}
+	if maxLength < minLength || maxLength > BcryptMaxPasswordLength {
+		return nil, errors.New("environment variable PASSWORD_MAX_LENGTH must be between PASSWORD_MIN_LENGTH and 72")
+	}
+
+	requireLower, err := getEnvBool(passwordRequireLowerName, defaultPasswordRequireLower)
+	if err != nil {
+		return nil, err
+	}
+
+	requireUpper, err := getEnvBool(passwordRequireUpperName, defaultPasswordRequireUpper)
+	if err != nil {
+		return nil, err
+	}
+
+	requireDigit, err := getEnvBool(passwordRequireDigitName, defaultPasswordRequireDigit)
+	if err != nil {
+		return nil, err
+	}
+
+	requireSpecial, err := getEnvBool(passwordRequireSpecialName, defaultPasswordRequireSpecial)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasswordPolicyConfig{
+		MinLength:      minLength,
+		MaxLength:      maxLength,
+		RequireLower:   requireLower,
+		RequireUpper:   requireUpper,
+		RequireDigit:   requireDigit,
+		RequireSpecial: requireSpecial,
+	}, nil
+}
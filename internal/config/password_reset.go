@@ -0,0 +1,32 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	passwordResetTokenLifeTimeName = "PASSWORD_RESET_TOKEN_LIFE_TIME_MINUTE"
+)
+
+const (
+	defaultPasswordResetTokenLifeTime = 30
+)
+
+type PasswordResetConfig struct {
+	TokenLifeTime time.Duration
+}
+
+func NewPasswordResetConfig() (*PasswordResetConfig, error) {
+	lifeTime, err := getEnvInt(passwordResetTokenLifeTimeName, defaultPasswordResetTokenLifeTime)
+	if err != nil {
+		return nil, err
+	}
+	if lifeTime < 1 {
+		return nil, errors.New("environment variable PASSWORD_RESET_TOKEN_LIFE_TIME_MINUTE must be positive")
+	}
+
+	return &PasswordResetConfig{
+		TokenLifeTime: time.Duration(lifeTime) * time.Minute,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	rateLimitDefaultName = "RATE_LIMIT_DEFAULT"
+	rateLimitMethodsName = "RATE_LIMIT_METHODS"
+)
+
+// Лимит задается как "<запросов в секунду>:<размер корзины>", 0 запросов в секунду - без ограничения
+const (
+	defaultRateLimit        = "20:40"
+	defaultRateLimitMethods = "SignUp=0.05:3,ResendConfirmation=0.05:3,ResetPassword=0.05:3,RequestLoginCode=0.05:3,SignIn=1:10,SignInWithCode=1:10"
+)
+
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type RateLimitConfig struct {
+	Default RateLimit
+	// Лимиты по коротким именам RPC, например SignUp, и HTTP эндпоинтов Introspect и Revoke
+	Methods map[string]RateLimit
+}
+
+func NewRateLimitConfig() (*RateLimitConfig, error) {
+	def, err := parseRateLimit(rateLimitDefaultName, getEnvString(rateLimitDefaultName, defaultRateLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make(map[string]RateLimit)
+	for _, entry := range strings.Split(getEnvString(rateLimitMethodsName, defaultRateLimitMethods), ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+
+		method, value, ok := strings.Cut(entry, "=")
+		if !ok || len(method) == 0 {
+			return nil, fmt.Errorf("environment variable %s has invalid entry %q", rateLimitMethodsName, entry)
+		}
+
+		limit, err := parseRateLimit(rateLimitMethodsName, value)
+		if err != nil {
+			return nil, err
+		}
+		methods[method] = limit
+	}
+
+	return &RateLimitConfig{
+		Default: def,
+		Methods: methods,
+	}, nil
+}
+
+// CheckMethods проверяет, что лимиты заданы только для существующих RPC: опечатка в имени
+// иначе молча оставила бы метод с лимитом по умолчанию
+func (c *RateLimitConfig) CheckMethods(known []string) error {
+	for method := range c.Methods {
+		if !slices.Contains(known, method) {
+			return fmt.Errorf("environment variable %s has unknown method %q", rateLimitMethodsName, method)
+		}
+	}
+
+	return nil
+}
+
+func parseRateLimit(name string, value string) (RateLimit, error) {
+	rateValue, burstValue, ok := strings.Cut(value, ":")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("environment variable %s has invalid limit %q", name, value)
+	}
+
+	rate, err := strconv.ParseFloat(rateValue, 64)
+	if err != nil || rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return RateLimit{}, fmt.Errorf("environment variable %s has invalid rate %q", name, rateValue)
+	}
+
+	burst, err := strconv.Atoi(burstValue)
+	if err != nil || burst < 1 {
+		return RateLimit{}, fmt.Errorf("environment variable %s has invalid burst %q", name, burstValue)
+	}
+
+	return RateLimit{Rate: rate, Burst: burst}, nil
+}
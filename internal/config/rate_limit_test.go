@@ -0,0 +1,35 @@
+package config
+
+import "testing"
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{value: "20:40", want: RateLimit{Rate: 20, Burst: 40}},
+		{value: "0.05:3", want: RateLimit{Rate: 0.05, Burst: 3}},
+		{value: "0:1", want: RateLimit{Rate: 0, Burst: 1}},
+		{value: "20", wantErr: true},
+		{value: "-1:5", wantErr: true},
+		{value: "NaN:5", wantErr: true},
+		{value: "Inf:5", wantErr: true},
+		{value: "+Inf:5", wantErr: true},
+		{value: "1:0", wantErr: true},
+		{value: "1:x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRateLimit(rateLimitDefaultName, tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseRateLimit(%q) = %+v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseRateLimit(%q) = %+v, %v, want %+v", tt.value, got, err, tt.want)
+		}
+	}
+}
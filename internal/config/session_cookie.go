@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	sessionCookieSecureName   = "SESSION_COOKIE_SECURE"
+	sessionCookieSameSiteName = "SESSION_COOKIE_SAMESITE"
+	sessionCookieDomainName   = "SESSION_COOKIE_DOMAIN"
+)
+
+const (
+	defaultSessionCookieSecure   = true
+	defaultSessionCookieSameSite = "strict"
+)
+
+type SessionCookieConfig struct {
+	// Secure можно отключить только для локальной разработки по http
+	Secure   bool
+	SameSite http.SameSite
+	Domain   string
+}
+
+func NewSessionCookieConfig() (*SessionCookieConfig, error) {
+	secure, err := getEnvBool(sessionCookieSecureName, defaultSessionCookieSecure)
+	if err != nil {
+		return nil, err
+	}
+
+	var sameSite http.SameSite
+	switch value := strings.ToLower(getEnvString(sessionCookieSameSiteName, defaultSessionCookieSameSite)); value {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "lax":
+		sameSite = http.SameSiteLaxMode
+	default:
+		return nil, fmt.Errorf("environment variable SESSION_COOKIE_SAMESITE has unsupported value %q", value)
+	}
+
+	return &SessionCookieConfig{
+		Secure:   secure,
+		SameSite: sameSite,
+		Domain:   getEnvString(sessionCookieDomainName, ""),
+	}, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	tokenPurgeIntervalName  = "TOKEN_PURGE_INTERVAL_MINUTE"
+	tokenPurgeBatchSizeName = "TOKEN_PURGE_BATCH_SIZE"
+)
+
+const (
+	defaultTokenPurgeInterval  = 60
+	defaultTokenPurgeBatchSize = 1000
+)
+
+type TokenPurgeConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewTokenPurgeConfig() (*TokenPurgeConfig, error) {
+	interval, err := getEnvInt(tokenPurgeIntervalName, defaultTokenPurgeInterval)
+	if err != nil {
+		return nil, err
+	}
+	if interval < 1 {
+		return nil, errors.New("environment variable TOKEN_PURGE_INTERVAL_MINUTE must be positive")
+	}
+
+	batchSize, err := getEnvInt(tokenPurgeBatchSizeName, defaultTokenPurgeBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if batchSize < 1 {
+		return nil, errors.New("environment variable TOKEN_PURGE_BATCH_SIZE must be positive")
+	}
+
+	return &TokenPurgeConfig{
+		Interval:  time.Duration(interval) * time.Minute,
+		BatchSize: batchSize,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	tokenStoreBackendName = "TOKEN_STORE_BACKEND"
+	redisAddrName         = "REDIS_ADDR"
+	redisPasswordName     = "REDIS_PASSWORD"
+	redisDBName           = "REDIS_DB"
+)
+
+const (
+	TokenStorePostgres = "postgres"
+	TokenStoreRedis    = "redis"
+)
+
+const (
+	defaultTokenStoreBackend = TokenStorePostgres
+	defaultRedisAddr         = "localhost:6379"
+	defaultRedisDB           = 0
+)
+
+type TokenStoreConfig struct {
+	Backend string
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+}
+
+func NewTokenStoreConfig() (*TokenStoreConfig, error) {
+	backend := getEnvString(tokenStoreBackendName, defaultTokenStoreBackend)
+	if backend != TokenStorePostgres && backend != TokenStoreRedis {
+		return nil, fmt.Errorf("environment variable TOKEN_STORE_BACKEND has unsupported value %q", backend)
+	}
+
+	redisDB, err := getEnvInt(redisDBName, defaultRedisDB)
+	if err != nil {
+		return nil, err
+	}
+	if redisDB < 0 {
+		return nil, errors.New("environment variable REDIS_DB must not be negative")
+	}
+
+	return &TokenStoreConfig{
+		Backend:       backend,
+		RedisAddr:     getEnvString(redisAddrName, defaultRedisAddr),
+		RedisPassword: getEnvString(redisPasswordName, ""),
+		RedisDB:       redisDB,
+	}, nil
+}
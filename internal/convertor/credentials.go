@@ -7,16 +7,20 @@ import (
 
 func CredentialsEntityToCredentialsDto(c entity.Credentials) dto.CredentialsDto {
 	return dto.CredentialsDto{
-		ID:       c.ID,
-		Email:    c.Email,
-		Password: c.Password,
+		ID:            c.ID,
+		Email:         c.Email,
+		Password:      c.Password,
+		EmailVerified: c.EmailVerified,
+		Roles:         dto.JoinRoles(c.Roles),
 	}
 }
 
 func CredentialsDtoToCredentialsEntity(c dto.CredentialsDto) entity.Credentials {
 	return entity.Credentials{
-		ID:       c.ID,
-		Email:    c.Email,
-		Password: c.Password,
+		ID:            c.ID,
+		Email:         c.Email,
+		Password:      c.Password,
+		EmailVerified: c.EmailVerified,
+		Roles:         dto.SplitRoles(c.Roles),
 	}
 }
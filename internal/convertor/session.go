@@ -0,0 +1,20 @@
+package convertor
+
+import (
+	"AuthService/internal/entity"
+	proto "AuthService/pkg/api/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func SessionEntityToProto(s entity.Session, current bool) *proto.Session {
+	return &proto.Session{
+		Id:          s.ID,
+		UserAgent:   s.UserAgent,
+		Ip:          s.IP,
+		CreatedAt:   timestamppb.New(s.CreatedAt),
+		LastUsedAt:  timestamppb.New(s.LastUsedAt),
+		Current:     current,
+		LoginMethod: s.LoginMethod,
+	}
+}
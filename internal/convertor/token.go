@@ -3,6 +3,9 @@ package convertor
 import (
 	"AuthService/internal/dto"
 	"AuthService/internal/entity"
+	proto "AuthService/pkg/api/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TokenEntityToTokenDto(t entity.Token) dto.TokenDto {
@@ -13,6 +16,9 @@ func TokenEntityToTokenDto(t entity.Token) dto.TokenDto {
 		Revoked:   t.Revoked,
 		IssuedAt:  t.IssuedAt,
 		ExpiresAt: t.ExpiresAt,
+		FamilyId:  t.FamilyId,
+		Rotated:   t.Rotated,
+		RevokedAt: t.RevokedAt,
 	}
 }
 
@@ -24,5 +30,20 @@ func TokenDtoToTokenEntity(t dto.TokenDto) entity.Token {
 		Revoked:   t.Revoked,
 		IssuedAt:  t.IssuedAt,
 		ExpiresAt: t.ExpiresAt,
+		FamilyId:  t.FamilyId,
+		Rotated:   t.Rotated,
+		RevokedAt: t.RevokedAt,
+	}
+}
+
+func TokenClaimsToProto(c entity.TokenClaims) *proto.VerifyAccessTokenResponse {
+	return &proto.VerifyAccessTokenResponse{
+		UserId:        c.SubjectId,
+		Email:         c.Email,
+		ExpiresAt:     timestamppb.New(c.ExpiresAt),
+		IssuedAt:      timestamppb.New(c.IssuedAt),
+		SessionId:     c.SessionId,
+		Roles:         c.Roles,
+		EmailVerified: c.EmailVerified,
 	}
 }
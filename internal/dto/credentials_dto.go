@@ -1,11 +1,16 @@
 package dto
 
-import "AuthService/internal/entity"
+import (
+	"AuthService/internal/entity"
+	"strings"
+)
 
 type CredentialsDto struct {
-	ID       int64  `gorm:"column_id:id,primaryKey"`
-	Email    string `gorm:"column_id:email,unique"`
-	Password string `gorm:"column_id:password"`
+	ID            int64  `gorm:"column_id:id,primaryKey"`
+	Email         string `gorm:"column_id:email,unique"`
+	Password      string `gorm:"column_id:password"`
+	EmailVerified bool   `gorm:"column:email_verified"`
+	Roles         string `gorm:"column:roles;default:user"`
 }
 
 func (CredentialsDto) TableName() string {
@@ -14,8 +19,22 @@ func (CredentialsDto) TableName() string {
 
 func (c CredentialsDto) ToCredentialsEntity() entity.Credentials {
 	return entity.Credentials{
-		ID:       c.ID,
-		Email:    c.Email,
-		Password: c.Password,
+		ID:            c.ID,
+		Email:         c.Email,
+		Password:      c.Password,
+		EmailVerified: c.EmailVerified,
+		Roles:         SplitRoles(c.Roles),
 	}
 }
+
+// Роли хранятся в одной колонке через запятую
+func SplitRoles(roles string) []string {
+	if len(roles) == 0 {
+		return nil
+	}
+	return strings.Split(roles, ",")
+}
+
+func JoinRoles(roles []string) string {
+	return strings.Join(roles, ",")
+}
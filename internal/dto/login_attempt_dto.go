@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type LoginAttemptDto struct {
+	Key           string           `gorm:"column:key;primaryKey"`
+	Failures      int              `gorm:"column:failures"`
+	LastFailureAt pgtype.Timestamp `gorm:"column:last_failure_at"`
+	LockedUntil   pgtype.Timestamp `gorm:"column:locked_until"`
+}
+
+func (LoginAttemptDto) TableName() string {
+	return "login_attempts"
+}
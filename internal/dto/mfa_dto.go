@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type TOTPDto struct {
+	CredentialsId   int64            `gorm:"column:credentials_id;primaryKey"`
+	SecretEncrypted string           `gorm:"column:secret_encrypted"`
+	ConfirmedAt     pgtype.Timestamp `gorm:"column:confirmed_at"`
+	LastUsedStep    int64            `gorm:"column:last_used_step"`
+	CreatedAt       time.Time        `gorm:"column:created_at"`
+}
+
+func (TOTPDto) TableName() string {
+	return "credentials_totp"
+}
+
+type RecoveryCodeDto struct {
+	ID            int64     `gorm:"column:id;primaryKey"`
+	CredentialsId int64     `gorm:"column:credentials_id"`
+	CodeHash      string    `gorm:"column:code_hash"`
+	Used          bool      `gorm:"column:used"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+}
+
+func (RecoveryCodeDto) TableName() string {
+	return "recovery_codes"
+}
+
+type MFAChallengeDto struct {
+	ID            int64     `gorm:"column:id;primaryKey"`
+	CredentialsId int64     `gorm:"column:credentials_id"`
+	TokenHash     string    `gorm:"column:token_hash"`
+	Attempts      int       `gorm:"column:attempts"`
+	Used          bool      `gorm:"column:used"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	ExpiresAt     time.Time `gorm:"column:expires_at"`
+	LoginMethod   string    `gorm:"column:login_method"`
+}
+
+func (MFAChallengeDto) TableName() string {
+	return "mfa_challenges"
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// Таблицы кодов из письма одинаковы по структуре, таблица выбирается в OneTimeCodesRepository
+const (
+	ConfirmationCodeTable = "email_confirmation_code"
+	LoginCodeTable        = "email_login_code"
+)
+
+type OneTimeCodeDto struct {
+	ID            int64     `gorm:"column:id;primaryKey"`
+	CredentialsId int64     `gorm:"column:credentials_id"`
+	CodeHash      string    `gorm:"column:code_hash"`
+	Attempts      int       `gorm:"column:attempts"`
+	Used          bool      `gorm:"column:used"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	ExpiresAt     time.Time `gorm:"column:expires_at"`
+}
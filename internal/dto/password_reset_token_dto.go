@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type PasswordResetTokenDto struct {
+	ID            int64     `gorm:"column:id;primaryKey"`
+	CredentialsId int64     `gorm:"column:credentials_id"`
+	TokenHash     string    `gorm:"column:token_hash"`
+	Used          bool      `gorm:"column:used"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	ExpiresAt     time.Time `gorm:"column:expires_at"`
+}
+
+func (PasswordResetTokenDto) TableName() string {
+	return "password_reset_token"
+}
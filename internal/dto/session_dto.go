@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"AuthService/internal/entity"
+	"time"
+)
+
+type SessionDto struct {
+	ID          string    `gorm:"column:id;primaryKey"`
+	SubjectId   int64     `gorm:"column:subject_id"`
+	UserAgent   string    `gorm:"column:user_agent"`
+	IP          string    `gorm:"column:ip"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	LastUsedAt  time.Time `gorm:"column:last_used_at"`
+	ExpiresAt   time.Time `gorm:"column:expires_at"`
+	LoginMethod string    `gorm:"column:login_method"`
+}
+
+func (SessionDto) TableName() string {
+	return "sessions"
+}
+
+func (s SessionDto) ToSessionEntity() entity.Session {
+	return entity.Session{
+		ID:          s.ID,
+		SubjectId:   s.SubjectId,
+		UserAgent:   s.UserAgent,
+		IP:          s.IP,
+		CreatedAt:   s.CreatedAt,
+		LastUsedAt:  s.LastUsedAt,
+		ExpiresAt:   s.ExpiresAt,
+		LoginMethod: s.LoginMethod,
+	}
+}
@@ -11,6 +11,9 @@ type TokenDto struct {
 	Revoked   bool             `gorm:"column_id:revoked"`
 	IssuedAt  pgtype.Timestamp `gorm:"column_id:issued_at"`
 	ExpiresAt pgtype.Timestamp `gorm:"column:expires_at"`
+	FamilyId  string           `gorm:"column:family_id"`
+	Rotated   bool             `gorm:"column:rotated"`
+	RevokedAt pgtype.Timestamp `gorm:"column:revoked_at"`
 }
 
 func (c TokenDto) TableName() string {
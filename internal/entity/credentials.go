@@ -1,7 +1,9 @@
 package entity
 
 type Credentials struct {
-	ID       int64
-	Email    string
-	Password string
+	ID            int64
+	Email         string
+	Password      string
+	EmailVerified bool
+	Roles         []string
 }
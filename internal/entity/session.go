@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// Способ входа, которым создана сессия
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodEmailCode = "email_code"
+)
+
+type Session struct {
+	ID          string
+	SubjectId   int64
+	UserAgent   string
+	IP          string
+	CreatedAt   time.Time
+	LastUsedAt  time.Time
+	ExpiresAt   time.Time
+	LoginMethod string
+}
+
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
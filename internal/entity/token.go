@@ -1,6 +1,10 @@
 package entity
 
-import "github.com/jackc/pgx/v5/pgtype"
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 type Token struct {
 	JTI       string
@@ -9,4 +13,20 @@ type Token struct {
 	Revoked   bool
 	IssuedAt  pgtype.Timestamp
 	ExpiresAt pgtype.Timestamp
+	FamilyId  string
+	Rotated   bool
+	RevokedAt pgtype.Timestamp
+}
+
+type TokenClaims struct {
+	JTI       string
+	SubjectId int64
+	Email     string
+	SessionId string
+	TokenType string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	Roles         []string
+	EmailVerified bool
 }
@@ -0,0 +1,147 @@
+package interceptor
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/metrics"
+	"AuthService/internal/utils"
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Как часто удаляются корзины, которые успели заполниться и больше ничего не ограничивают
+const rateLimitSweepInterval = time.Minute
+
+// Clock отделяет лимитер от системного времени, чтобы его можно было проверять с поддельными часами.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimiter - token bucket на каждую пару (метод, клиент). Клиент определяется по IP адресу из gRPC peer.
+type RateLimiter struct {
+	cfg   *config.RateLimitConfig
+	clock Clock
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewRateLimiter(cfg *config.RateLimitConfig, clock Clock) *RateLimiter {
+	return &RateLimiter{
+		cfg:       cfg,
+		clock:     clock,
+		buckets:   make(map[string]*bucket),
+		lastSweep: clock.Now(),
+	}
+}
+
+func (rl *RateLimiter) limit(method string) config.RateLimit {
+	if limit, ok := rl.cfg.Methods[method]; ok {
+		return limit
+	}
+	return rl.cfg.Default
+}
+
+// Allow забирает токен из корзины; если токенов нет, возвращает время до появления следующего.
+func (rl *RateLimiter) Allow(method string, caller string) (bool, time.Duration) {
+	limit := rl.limit(method)
+	if limit.Rate == 0 {
+		return true, 0
+	}
+
+	now := rl.clock.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.sweep(now)
+
+	key := method + "|" + caller
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		rl.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.updated = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait
+}
+
+func (rl *RateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	rl.lastSweep = now
+
+	for key, b := range rl.buckets {
+		method, _, _ := strings.Cut(key, "|")
+		limit := rl.limit(method)
+		if b.tokens+now.Sub(b.updated).Seconds()*limit.Rate >= float64(limit.Burst) {
+			delete(rl.buckets, key)
+		}
+	}
+}
+
+func (rl *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+
+		caller := utils.ClientInfoFromContext(ctx).IP
+		if len(caller) == 0 {
+			caller = "unknown"
+		}
+
+		if ok, wait := rl.Allow(method, caller); !ok {
+			metrics.IncRateLimitRejected(method)
+			return nil, rateLimitExceeded(wait)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func rateLimitExceeded(wait time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, "Rate limit exceeded").WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(wait.Round(time.Second) + time.Second),
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "Rate limit exceeded")
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,148 @@
+package interceptor
+
+import (
+	"AuthService/internal/config"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestRateLimiter(def config.RateLimit, methods map[string]config.RateLimit) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return NewRateLimiter(&config.RateLimitConfig{Default: def, Methods: methods}, clock), clock
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	rl, _ := newTestRateLimiter(config.RateLimit{Rate: 1, Burst: 3}, nil)
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := rl.Allow("SignIn", "10.0.0.1"); !ok {
+			t.Fatalf("request %d within burst was rejected", i+1)
+		}
+	}
+
+	if ok, _ := rl.Allow("SignIn", "10.0.0.1"); ok {
+		t.Fatal("request over burst was allowed")
+	}
+}
+
+func TestRateLimiterRetryAfter(t *testing.T) {
+	rl, clock := newTestRateLimiter(config.RateLimit{Rate: 0.5, Burst: 1}, nil)
+
+	rl.Allow("SignIn", "10.0.0.1")
+
+	ok, wait := rl.Allow("SignIn", "10.0.0.1")
+	if ok {
+		t.Fatal("request over burst was allowed")
+	}
+	if wait != 2*time.Second {
+		t.Fatalf("wait = %v, want 2s", wait)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if _, wait := rl.Allow("SignIn", "10.0.0.1"); wait != 1500*time.Millisecond {
+		t.Fatalf("wait after 500ms = %v, want 1.5s", wait)
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	rl, clock := newTestRateLimiter(config.RateLimit{Rate: 2, Burst: 2}, nil)
+
+	rl.Allow("SignIn", "10.0.0.1")
+	rl.Allow("SignIn", "10.0.0.1")
+	if ok, _ := rl.Allow("SignIn", "10.0.0.1"); ok {
+		t.Fatal("empty bucket allowed a request")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if ok, _ := rl.Allow("SignIn", "10.0.0.1"); !ok {
+		t.Fatal("refilled token was not available")
+	}
+	if ok, _ := rl.Allow("SignIn", "10.0.0.1"); ok {
+		t.Fatal("only one token must have been refilled")
+	}
+
+	// Корзина не наполняется выше Burst, сколько бы времени ни прошло
+	clock.Advance(time.Hour)
+	for i := 0; i < 2; i++ {
+		if ok, _ := rl.Allow("SignIn", "10.0.0.1"); !ok {
+			t.Fatalf("request %d after refill was rejected", i+1)
+		}
+	}
+	if ok, _ := rl.Allow("SignIn", "10.0.0.1"); ok {
+		t.Fatal("bucket was refilled above burst")
+	}
+}
+
+func TestRateLimiterKeysByMethodAndCaller(t *testing.T) {
+	rl, _ := newTestRateLimiter(config.RateLimit{Rate: 1, Burst: 1}, nil)
+
+	rl.Allow("SignIn", "10.0.0.1")
+
+	if ok, _ := rl.Allow("SignIn", "10.0.0.2"); !ok {
+		t.Fatal("another caller shares the bucket")
+	}
+	if ok, _ := rl.Allow("SignUp", "10.0.0.1"); !ok {
+		t.Fatal("another method shares the bucket")
+	}
+}
+
+func TestRateLimiterMethodLimits(t *testing.T) {
+	rl, _ := newTestRateLimiter(config.RateLimit{Rate: 1, Burst: 1}, map[string]config.RateLimit{
+		"SignUp":            {Rate: 1, Burst: 2},
+		"VerifyAccessToken": {Rate: 0, Burst: 1},
+	})
+
+	rl.Allow("SignUp", "10.0.0.1")
+	if ok, _ := rl.Allow("SignUp", "10.0.0.1"); !ok {
+		t.Fatal("method limit was not applied")
+	}
+
+	// Нулевая частота снимает ограничение
+	for i := 0; i < 100; i++ {
+		if ok, _ := rl.Allow("VerifyAccessToken", "10.0.0.1"); !ok {
+			t.Fatal("unlimited method was rejected")
+		}
+	}
+	if len(rl.buckets) != 1 {
+		t.Fatalf("buckets = %d, want 1: unlimited methods must not allocate buckets", len(rl.buckets))
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	rl, clock := newTestRateLimiter(config.RateLimit{Rate: 1, Burst: 100}, nil)
+
+	rl.Allow("SignIn", "10.0.0.1")
+	for i := 0; i < 100; i++ {
+		rl.Allow("SignIn", "10.0.0.2")
+	}
+
+	// До интервала очистки корзины не удаляются
+	clock.Advance(rateLimitSweepInterval - time.Second)
+	rl.Allow("SignIn", "10.0.0.3")
+	if len(rl.buckets) != 3 {
+		t.Fatalf("buckets before sweep = %d, want 3", len(rl.buckets))
+	}
+
+	// К моменту очистки первая корзина наполнилась, вторая набрала только 60 токенов из 100
+	clock.Advance(time.Second)
+	rl.Allow("SignIn", "10.0.0.3")
+
+	if _, ok := rl.buckets["SignIn|10.0.0.1"]; ok {
+		t.Fatal("full bucket was not swept")
+	}
+	if _, ok := rl.buckets["SignIn|10.0.0.2"]; !ok {
+		t.Fatal("bucket that still limits the caller was swept")
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIP      = "ip"
+)
+
+var loginFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "login_protection",
+	Name:      "failures_total",
+}, []string{"scope"})
+
+var loginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "login_protection",
+	Name:      "lockouts_total",
+}, []string{"scope"})
+
+var loginRejected = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "login_protection",
+	Name:      "rejected_total",
+})
+
+var loginProtectionConfig = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "login_protection",
+	Name:      "config",
+}, []string{"param"})
+
+func IncLoginFailure(scope string) {
+	loginFailures.WithLabelValues(scope).Inc()
+}
+
+func IncLoginLockout(scope string) {
+	loginLockouts.WithLabelValues(scope).Inc()
+}
+
+func IncLoginRejected() {
+	loginRejected.Inc()
+}
+
+// SetLoginProtectionConfig публикует текущие пороги, чтобы их было видно рядом с графиками
+func SetLoginProtectionConfig(param string, value float64) {
+	loginProtectionConfig.WithLabelValues(param).Set(value)
+}
@@ -0,0 +1,15 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var rateLimitRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "rate_limit",
+	Name:      "rejected_total",
+}, []string{"method"})
+
+func IncRateLimitRejected(method string) {
+	rateLimitRejected.WithLabelValues(method).Inc()
+}
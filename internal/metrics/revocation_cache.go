@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var revocationCacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "revocation_cache",
+	Name:      "size",
+})
+
+var revocationCacheSyncErrors = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "revocation_cache",
+	Name:      "sync_errors_total",
+})
+
+func SetRevocationCacheSize(size int) {
+	revocationCacheSize.Set(float64(size))
+}
+
+func IncRevocationCacheSyncErrors() {
+	revocationCacheSyncErrors.Inc()
+}
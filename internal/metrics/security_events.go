@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	SecurityEventRefreshTokenReuse = "refresh_token_reuse"
+)
+
+var securityEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "security",
+	Name:      "events_total",
+}, []string{"event"})
+
+func IncSecurityEvent(event string) {
+	securityEvents.WithLabelValues(event).Inc()
+}
@@ -7,9 +7,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func Listen(host string) error {
+func Listen(host string, mux *http.ServeMux) error {
 	log.Printf("Run metrics on: %v", host)
-	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 
 	return http.ListenAndServe(host, mux)
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var tokenPurgeDeletedRows = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "token_purge",
+	Name:      "deleted_rows_total",
+})
+
+var tokenPurgeErrors = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "token_purge",
+	Name:      "errors_total",
+})
+
+func AddTokenPurgeDeletedRows(count int64) {
+	tokenPurgeDeletedRows.Add(float64(count))
+}
+
+func IncTokenPurgeErrors() {
+	tokenPurgeErrors.Inc()
+}
@@ -0,0 +1,62 @@
+package mfa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+// SecretCipher шифрует секреты TOTP перед сохранением в базу. Шифротекст хранится как
+// base64(nonce || sealed), credentials_id передается как associated data, чтобы секрет
+// нельзя было перенести в строку другого пользователя.
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+func NewSecretCipher(key []byte) (*SecretCipher, error) {
+	if len(key) != 32 {
+		return nil, errors.New("MFA encryption key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretCipher{aead: aead}, nil
+}
+
+func (sc *SecretCipher) Encrypt(plaintext string, associatedData []byte) (string, error) {
+	nonce := make([]byte, sc.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := sc.aead.Seal(nonce, nonce, []byte(plaintext), associatedData)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (sc *SecretCipher) Decrypt(ciphertext string, associatedData []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < sc.aead.NonceSize() {
+		return "", errors.New("MFA secret ciphertext is too short")
+	}
+
+	nonce, sealed := data[:sc.aead.NonceSize()], data[sc.aead.NonceSize():]
+	plaintext, err := sc.aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,111 @@
+package mfa
+
+import (
+	"testing"
+	"time"
+)
+
+// Секрет тестовых векторов RFC 6238 для SHA1 - ASCII строка "12345678901234567890"
+var rfc6238Secret = secretEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// В RFC приведены 8-значные коды, здесь сравниваются их последние 6 цифр
+func TestCodeRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfc6238Secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(T=%d): %v", tt.unix, err)
+		}
+		if code != tt.want {
+			t.Fatalf("Code(T=%d) = %s, want %s", tt.unix, code, tt.want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{name: "Current", offset: 0, ok: true},
+		{name: "PreviousStep", offset: -1, ok: true},
+		{name: "NextStep", offset: 1, ok: true},
+		{name: "TwoStepsBehind", offset: -2, ok: false},
+		{name: "TwoStepsAhead", offset: 2, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfc6238Secret, current+tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, ok, err := Validate(rfc6238Secret, code, now, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.ok {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Fatalf("Validate step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateReplay(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, err := Code(rfc6238Secret, Step(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, ok, err := Validate(rfc6238Secret, code, now, 0)
+	if err != nil || !ok {
+		t.Fatalf("first use: ok = %v, err = %v", ok, err)
+	}
+
+	// Тот же код с сохраненным шагом последнего входа больше не принимается, даже в пределах окна
+	if _, ok, err := Validate(rfc6238Secret, code, now, step); err != nil || ok {
+		t.Fatalf("replay: ok = %v, err = %v", ok, err)
+	}
+	if _, ok, err := Validate(rfc6238Secret, code, now.Add(totpPeriod*time.Second), step); err != nil || ok {
+		t.Fatalf("replay in the next step: ok = %v, err = %v", ok, err)
+	}
+
+	// Код более раннего шага тоже отвергается, а следующего - принимается
+	previous, _ := Code(rfc6238Secret, step-1)
+	if _, ok, _ := Validate(rfc6238Secret, previous, now, step); ok {
+		t.Fatal("code older than the last used step was accepted")
+	}
+	next, _ := Code(rfc6238Secret, step+1)
+	if _, ok, _ := Validate(rfc6238Secret, next, now, step); !ok {
+		t.Fatal("code newer than the last used step was rejected")
+	}
+}
+
+func TestValidateRejectsMalformedCode(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "94287082"} {
+		if _, ok, err := Validate(rfc6238Secret, code, now, 0); err != nil || ok {
+			t.Fatalf("Validate(%q): ok = %v, err = %v", code, ok, err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"AuthService/internal/dto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CredentialsRepository struct {
@@ -15,10 +16,15 @@ func NewCredentialsRepository() *CredentialsRepository {
 
 func (cr *CredentialsRepository) GetCountByEmail(db *gorm.DB, email string) (int64, error) {
 	var count int64
-	err := db.Model(new(dto.CredentialsDto)).Where("email = ?", email).Count(&count).Error
+	err := db.Model(new(dto.CredentialsDto)).Where("LOWER(email) = LOWER(?)", email).Count(&count).Error
 	return count, err
 }
 
 func (cr *CredentialsRepository) GetByEmail(db *gorm.DB, email string, dto *dto.CredentialsDto) error {
-	return db.Where("email = ?", email).Take(dto).Error
+	return db.Where("LOWER(email) = LOWER(?)", email).Take(dto).Error
+}
+
+// GetByEmailForUpdate блокирует строку пользователя до конца транзакции
+func (cr *CredentialsRepository) GetByEmailForUpdate(db *gorm.DB, email string, dto *dto.CredentialsDto) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("LOWER(email) = LOWER(?)", email).Take(dto).Error
 }
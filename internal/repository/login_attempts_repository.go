@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginAttemptsRepository struct {
+	Repository[dto.LoginAttemptDto]
+}
+
+func NewLoginAttemptsRepository() *LoginAttemptsRepository {
+	return &LoginAttemptsRepository{}
+}
+
+// GetByKeyForUpdate блокирует строку счетчика, создавая ее при первом обращении
+func (lr *LoginAttemptsRepository) GetByKeyForUpdate(db *gorm.DB, key string, dto *dto.LoginAttemptDto) error {
+	dto.Key = key
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(dto).Error; err != nil {
+		return err
+	}
+
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).Take(dto).Error
+}
+
+func (lr *LoginAttemptsRepository) DeleteByKey(db *gorm.DB, key string) error {
+	return db.Where("key = ?", key).Delete(&dto.LoginAttemptDto{}).Error
+}
+
+// DeleteStale удаляет пачку счетчиков без действующей блокировки, последняя неудача по которым была до before
+func (lr *LoginAttemptsRepository) DeleteStale(db *gorm.DB, now time.Time, before time.Time, limit int) (int64, error) {
+	batch := db.Session(&gorm.Session{NewDB: true}).Model(&dto.LoginAttemptDto{}).
+		Select("key").
+		Where("(last_failure_at IS NULL OR last_failure_at < ?) AND (locked_until IS NULL OR locked_until < ?)", before, now).
+		Limit(limit)
+
+	res := db.Where("key IN (?)", batch).Delete(&dto.LoginAttemptDto{})
+	return res.RowsAffected, res.Error
+}
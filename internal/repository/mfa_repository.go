@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TOTPRepository struct {
+	Repository[dto.TOTPDto]
+}
+
+func NewTOTPRepository() *TOTPRepository {
+	return &TOTPRepository{}
+}
+
+func (tr *TOTPRepository) GetByCredentialsId(db *gorm.DB, credentialsId int64, dto *dto.TOTPDto) error {
+	return db.Where("credentials_id = ?", credentialsId).Take(dto).Error
+}
+
+func (tr *TOTPRepository) GetByCredentialsIdForUpdate(db *gorm.DB, credentialsId int64, dto *dto.TOTPDto) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("credentials_id = ?", credentialsId).Take(dto).Error
+}
+
+func (tr *TOTPRepository) GetConfirmedCount(db *gorm.DB) (int64, error) {
+	var cnt int64
+	err := db.Model(&dto.TOTPDto{}).Where("confirmed_at IS NOT NULL").Count(&cnt).Error
+	return cnt, err
+}
+
+type RecoveryCodesRepository struct {
+	Repository[dto.RecoveryCodeDto]
+}
+
+func NewRecoveryCodesRepository() *RecoveryCodesRepository {
+	return &RecoveryCodesRepository{}
+}
+
+func (rr *RecoveryCodesRepository) CreateBatch(db *gorm.DB, dtos []dto.RecoveryCodeDto) error {
+	return db.Create(&dtos).Error
+}
+
+func (rr *RecoveryCodesRepository) DeleteAllByCredentialsId(db *gorm.DB, credentialsId int64) error {
+	return db.Where("credentials_id = ?", credentialsId).Delete(&dto.RecoveryCodeDto{}).Error
+}
+
+// UseByCodeHash помечает неиспользованный код как использованный и возвращает число затронутых строк
+func (rr *RecoveryCodesRepository) UseByCodeHash(db *gorm.DB, credentialsId int64, codeHash string) (int64, error) {
+	res := db.Model(&dto.RecoveryCodeDto{}).
+		Where("credentials_id = ? AND code_hash = ? AND used = ?", credentialsId, codeHash, false).
+		Update("used", true)
+	return res.RowsAffected, res.Error
+}
+
+type MFAChallengesRepository struct {
+	Repository[dto.MFAChallengeDto]
+}
+
+func NewMFAChallengesRepository() *MFAChallengesRepository {
+	return &MFAChallengesRepository{}
+}
+
+func (mr *MFAChallengesRepository) GetActiveByTokenHash(db *gorm.DB, tokenHash string, dto *dto.MFAChallengeDto) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used = ?", tokenHash, false).
+		Take(dto).Error
+}
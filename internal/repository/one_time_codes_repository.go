@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// OneTimeCodesRepository хранит коды из письма в таблице table: dto.ConfirmationCodeTable или dto.LoginCodeTable
+type OneTimeCodesRepository struct {
+	table string
+}
+
+func NewOneTimeCodesRepository(table string) *OneTimeCodesRepository {
+	return &OneTimeCodesRepository{
+		table: table,
+	}
+}
+
+func (or *OneTimeCodesRepository) Create(db *gorm.DB, dto *dto.OneTimeCodeDto) error {
+	return db.Table(or.table).Create(dto).Error
+}
+
+func (or *OneTimeCodesRepository) Update(db *gorm.DB, dto *dto.OneTimeCodeDto) error {
+	return db.Table(or.table).Save(dto).Error
+}
+
+func (or *OneTimeCodesRepository) GetLastActiveByCredentialsId(db *gorm.DB, credentialsId int64, dto *dto.OneTimeCodeDto) error {
+	return db.Table(or.table).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("credentials_id = ? AND used = ?", credentialsId, false).
+		Order("id DESC").
+		Take(dto).Error
+}
+
+func (or *OneTimeCodesRepository) InvalidateAllByCredentialsId(db *gorm.DB, credentialsId int64) error {
+	return db.Table(or.table).Where("credentials_id = ? AND used = ?", credentialsId, false).Update("used", true).Error
+}
+
+func (or *OneTimeCodesRepository) GetLastByCredentialsId(db *gorm.DB, credentialsId int64, dto *dto.OneTimeCodeDto) error {
+	return db.Table(or.table).Where("credentials_id = ?", credentialsId).Order("id DESC").Take(dto).Error
+}
+
+func (or *OneTimeCodesRepository) GetCountCreatedSince(db *gorm.DB, credentialsId int64, since time.Time) (int64, error) {
+	var count int64
+	err := db.Table(or.table).Where("credentials_id = ? AND created_at >= ?", credentialsId, since).Count(&count).Error
+	return count, err
+}
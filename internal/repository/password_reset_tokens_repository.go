@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetTokensRepository struct {
+	Repository[dto.PasswordResetTokenDto]
+}
+
+func NewPasswordResetTokensRepository() *PasswordResetTokensRepository {
+	return &PasswordResetTokensRepository{}
+}
+
+func (pr *PasswordResetTokensRepository) GetActiveByTokenHash(db *gorm.DB, tokenHash string, dto *dto.PasswordResetTokenDto) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used = ?", tokenHash, false).
+		Take(dto).Error
+}
+
+func (pr *PasswordResetTokensRepository) InvalidateAllByCredentialsId(db *gorm.DB, credentialsId int64) error {
+	return db.Model(&dto.PasswordResetTokenDto{}).Where("credentials_id = ? AND used = ?", credentialsId, false).Update("used", true).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type SessionsRepository struct {
+	Repository[dto.SessionDto]
+}
+
+func NewSessionsRepository() *SessionsRepository {
+	return &SessionsRepository{}
+}
+
+func (sr *SessionsRepository) GetActiveBySubjectIdAndIds(db *gorm.DB, subjectId int64, ids []string, now time.Time, dtos *[]dto.SessionDto) error {
+	return db.Where("subject_id = ? AND id IN ? AND expires_at > ?", subjectId, ids, now).
+		Order("last_used_at DESC").
+		Find(dtos).Error
+}
+
+func (sr *SessionsRepository) GetBySubjectIdAndId(db *gorm.DB, subjectId int64, id string, dto *dto.SessionDto) error {
+	return db.Where("subject_id = ? AND id = ?", subjectId, id).Take(dto).Error
+}
+
+func (sr *SessionsRepository) Touch(db *gorm.DB, id string, userAgent string, ip string, lastUsedAt time.Time, expiresAt time.Time) error {
+	return db.Model(&dto.SessionDto{}).Where("id = ?", id).Updates(map[string]any{
+		"user_agent":   userAgent,
+		"ip":           ip,
+		"last_used_at": lastUsedAt,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (sr *SessionsRepository) GetExpiredIdsForUpdate(db *gorm.DB, now time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := db.Model(&dto.SessionDto{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("expires_at < ?", now).
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (sr *SessionsRepository) DeleteByIds(db *gorm.DB, ids []string) (int64, error) {
+	res := db.Where("id IN ?", ids).Delete(&dto.SessionDto{})
+	return res.RowsAffected, res.Error
+}
@@ -2,25 +2,39 @@ package repository
 
 import (
 	"AuthService/internal/dto"
+	"errors"
 	"gorm.io/gorm"
+	"time"
 )
 
-type TokensRepository struct {
-	Repository[dto.TokenDto]
-}
+var ErrTokenAlreadyRotated = errors.New("token already rotated")
 
-func NewTokensRepository() *TokensRepository {
-	return &TokensRepository{}
+// TokenStore хранит состояние выданных токенов. Параметр db - текущая транзакция;
+// хранилища вне Postgres используют из нее только контекст запроса.
+// Если токен не найден, методы чтения и MarkRotatedByJTI возвращают gorm.ErrRecordNotFound,
+// повторная отметка об обмене - ErrTokenAlreadyRotated.
+type TokenStore interface {
+	Create(db *gorm.DB, dto *dto.TokenDto) error
+	GetTokensByJTIs(db *gorm.DB, jtis []string, dtos *[]dto.TokenDto) error
+	GetTokenByJTI(db *gorm.DB, jti string, dto *dto.TokenDto) error
+	GetTokenByJTIForUpdate(db *gorm.DB, jti string, dto *dto.TokenDto) error
+	RevokeAllTokensWithBySubjectId(db *gorm.DB, subjectId int64) error
+	RevokeAllTokensBySubjectIdExceptFamilyId(db *gorm.DB, subjectId int64, familyId string) error
+	RevokeTokenByJTI(db *gorm.DB, jti string) error
+	RevokeAllTokensByFamilyId(db *gorm.DB, familyId string) error
+	MarkRotatedByJTI(db *gorm.DB, jti string) error
+	GetRevokedAccessTokensSince(db *gorm.DB, since time.Time, dtos *[]dto.TokenDto) error
+	GetActiveFamilyIdsBySubjectId(db *gorm.DB, subjectId int64) ([]string, error)
+	DeleteExpiredTokens(db *gorm.DB, now time.Time, revokedBefore time.Time, limit int) (int64, error)
+	DeleteTokensByFamilyIds(db *gorm.DB, familyIds []string) (int64, error)
 }
 
-func (ts *TokensRepository) GetTokenByJTI(db *gorm.DB, jti string, dto *dto.TokenDto) error {
-	return db.Where("jti = ?", jti).Take(dto).Error
-}
-
-func (ts *TokensRepository) RevokeAllTokensWithBySubjectId(db *gorm.DB, subjectId int64) error {
-	return db.Model(&dto.TokenDto{}).Where("subject_id = ?", subjectId).Update("revoked", true).Error
+type TokensRepository struct {
+	TokenStore
 }
 
-func (ts *TokensRepository) RevokeTokenByJTI(db *gorm.DB, jti string) error {
-	return db.Model(&dto.TokenDto{}).Where("jti = ?", jti).Update("revoked", true).Error
+func NewTokensRepository(store TokenStore) *TokensRepository {
+	return &TokensRepository{
+		TokenStore: store,
+	}
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type PostgresTokenStore struct {
+	Repository[dto.TokenDto]
+}
+
+func NewPostgresTokenStore() *PostgresTokenStore {
+	return &PostgresTokenStore{}
+}
+
+func revokedColumns() map[string]any {
+	return map[string]any{"revoked": true, "revoked_at": gorm.Expr("NOW()")}
+}
+
+func (ts *PostgresTokenStore) GetTokenByJTI(db *gorm.DB, jti string, dto *dto.TokenDto) error {
+	return db.Where("jti = ?", jti).Take(dto).Error
+}
+
+func (ts *PostgresTokenStore) GetTokensByJTIs(db *gorm.DB, jtis []string, dtos *[]dto.TokenDto) error {
+	return db.Where("jti IN ?", jtis).Find(dtos).Error
+}
+
+func (ts *PostgresTokenStore) RevokeAllTokensWithBySubjectId(db *gorm.DB, subjectId int64) error {
+	return db.Model(&dto.TokenDto{}).Where("subject_id = ? AND revoked = ?", subjectId, false).Updates(revokedColumns()).Error
+}
+
+func (ts *PostgresTokenStore) RevokeAllTokensBySubjectIdExceptFamilyId(db *gorm.DB, subjectId int64, familyId string) error {
+	return db.Model(&dto.TokenDto{}).
+		Where("subject_id = ? AND family_id <> ? AND revoked = ?", subjectId, familyId, false).
+		Updates(revokedColumns()).Error
+}
+
+func (ts *PostgresTokenStore) RevokeTokenByJTI(db *gorm.DB, jti string) error {
+	return db.Model(&dto.TokenDto{}).Where("jti = ? AND revoked = ?", jti, false).Updates(revokedColumns()).Error
+}
+
+func (ts *PostgresTokenStore) GetTokenByJTIForUpdate(db *gorm.DB, jti string, dto *dto.TokenDto) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("jti = ?", jti).Take(dto).Error
+}
+
+func (ts *PostgresTokenStore) RevokeAllTokensByFamilyId(db *gorm.DB, familyId string) error {
+	return db.Model(&dto.TokenDto{}).Where("family_id = ? AND revoked = ?", familyId, false).Updates(revokedColumns()).Error
+}
+
+func (ts *PostgresTokenStore) MarkRotatedByJTI(db *gorm.DB, jti string) error {
+	res := db.Model(&dto.TokenDto{}).Where("jti = ? AND rotated = ?", jti, false).Update("rotated", true)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+
+	var cnt int64
+	if err := db.Model(&dto.TokenDto{}).Where("jti = ?", jti).Count(&cnt).Error; err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrTokenAlreadyRotated
+}
+
+func (ts *PostgresTokenStore) GetRevokedAccessTokensSince(db *gorm.DB, since time.Time, dtos *[]dto.TokenDto) error {
+	return db.Select("jti", "revoked_at").
+		Where("token_type = ? AND revoked = ? AND revoked_at >= ?", "access", true, since).
+		Find(dtos).Error
+}
+
+func (ts *PostgresTokenStore) GetActiveFamilyIdsBySubjectId(db *gorm.DB, subjectId int64) ([]string, error) {
+	var familyIds []string
+	err := db.Model(&dto.TokenDto{}).Distinct("family_id").
+		Where("subject_id = ? AND token_type = ? AND revoked = ?", subjectId, "refresh", false).
+		Pluck("family_id", &familyIds).Error
+	return familyIds, err
+}
+
+// Старые строки без expires_at удаляются, когда с их отзыва прошло больше срока жизни refresh токена
+func (ts *PostgresTokenStore) DeleteExpiredTokens(db *gorm.DB, now time.Time, revokedBefore time.Time, limit int) (int64, error) {
+	batch := db.Session(&gorm.Session{NewDB: true}).Model(&dto.TokenDto{}).
+		Select("jti").
+		Where("expires_at < ? OR (expires_at IS NULL AND revoked_at < ?)", now, revokedBefore).
+		Limit(limit)
+
+	res := db.Where("jti IN (?)", batch).Delete(&dto.TokenDto{})
+	return res.RowsAffected, res.Error
+}
+
+func (ts *PostgresTokenStore) DeleteTokensByFamilyIds(db *gorm.DB, familyIds []string) (int64, error) {
+	res := db.Where("family_id IN ?", familyIds).Delete(&dto.TokenDto{})
+	return res.RowsAffected, res.Error
+}
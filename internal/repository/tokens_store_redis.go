@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	redisTokenKeyPrefix   = "auth:token:"
+	redisFamilyKeyPrefix  = "auth:family:"
+	redisSubjectKeyPrefix = "auth:subject:"
+	redisRevokedAccessKey = "auth:revoked:access"
+)
+
+// KEYS[1] - журнал отозванных access токенов, KEYS[2..] - токены.
+// ARGV[1] - время отзыва, ARGV[2] - граница, до которой журнал обрезается.
+var redisRevokeScript = redis.NewScript(`
+local revoked = 0
+for i = 2, #KEYS do
+	if redis.call('HGET', KEYS[i], 'revoked') == '0' then
+		redis.call('HSET', KEYS[i], 'revoked', '1', 'revoked_at', ARGV[1])
+		if redis.call('HGET', KEYS[i], 'token_type') == 'access' then
+			redis.call('ZADD', KEYS[1], ARGV[1], redis.call('HGET', KEYS[i], 'jti'))
+		end
+		revoked = revoked + 1
+	end
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[2])
+return revoked
+`)
+
+var redisMarkRotatedScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+if redis.call('HGET', KEYS[1], 'rotated') == '1' then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'rotated', '1')
+return 1
+`)
+
+// RedisTokenStore хранит токены в Redis: каждый токен - хэш с TTL до истечения его срока,
+// так что записи об отозванных токенах удаляются сами. Блокировок строк нет, поэтому повторный
+// обмен refresh токена отсекается атомарно в MarkRotatedByJTI.
+//
+// Записи не участвуют в SQL транзакции и не откатываются вместе с ней. Если транзакция сорвется
+// после записи в Redis, остаются либо новые токены, которые клиент так и не получил (они истекут
+// по TTL), либо отметка об обмене старого refresh токена. Во втором случае повторный запрос с ним
+// считается повторным использованием и завершает сессию: при сбое хранилище отказывает во входе,
+// а не продлевает сессию.
+type RedisTokenStore struct {
+	client *redis.Client
+	// Максимальный срок жизни токена, используется как TTL индексов и журнала отзыва
+	ttl time.Duration
+}
+
+func NewRedisTokenStore(client *redis.Client, ttl time.Duration) *RedisTokenStore {
+	return &RedisTokenStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func redisContext(db *gorm.DB) context.Context {
+	if db != nil && db.Statement != nil && db.Statement.Context != nil {
+		return db.Statement.Context
+	}
+	return context.Background()
+}
+
+func redisTokenKey(jti string) string {
+	return redisTokenKeyPrefix + jti
+}
+
+func redisFamilyKey(familyId string) string {
+	return redisFamilyKeyPrefix + familyId
+}
+
+func redisSubjectKey(subjectId int64) string {
+	return redisSubjectKeyPrefix + strconv.FormatInt(subjectId, 10)
+}
+
+func redisBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func redisTimestamp(value pgtype.Timestamp) string {
+	if !value.Valid {
+		return ""
+	}
+	return strconv.FormatInt(value.Time.UnixMicro(), 10)
+}
+
+func parseRedisTimestamp(value string) pgtype.Timestamp {
+	micro, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: time.UnixMicro(micro).UTC(), Valid: true}
+}
+
+func (rs *RedisTokenStore) tokenTTL(dto *dto.TokenDto) time.Duration {
+	if !dto.ExpiresAt.Valid {
+		return rs.ttl
+	}
+	return max(time.Until(dto.ExpiresAt.Time), time.Second)
+}
+
+func (rs *RedisTokenStore) Create(db *gorm.DB, dto *dto.TokenDto) error {
+	ctx := redisContext(db)
+	tokenKey := redisTokenKey(dto.JTI)
+
+	_, err := rs.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, tokenKey,
+			"jti", dto.JTI,
+			"subject_id", dto.SubjectId,
+			"token_type", dto.TokenType,
+			"revoked", redisBool(dto.Revoked),
+			"issued_at", redisTimestamp(dto.IssuedAt),
+			"expires_at", redisTimestamp(dto.ExpiresAt),
+			"family_id", dto.FamilyId,
+			"rotated", redisBool(dto.Rotated),
+			"revoked_at", redisTimestamp(dto.RevokedAt),
+		)
+		pipe.Expire(ctx, tokenKey, rs.tokenTTL(dto))
+
+		pipe.SAdd(ctx, redisFamilyKey(dto.FamilyId), dto.JTI)
+		pipe.Expire(ctx, redisFamilyKey(dto.FamilyId), rs.ttl)
+
+		pipe.SAdd(ctx, redisSubjectKey(dto.SubjectId), dto.JTI)
+		pipe.Expire(ctx, redisSubjectKey(dto.SubjectId), rs.ttl)
+		return nil
+	})
+	return err
+}
+
+func (rs *RedisTokenStore) GetTokenByJTI(db *gorm.DB, jti string, dto *dto.TokenDto) error {
+	values, err := rs.client.HGetAll(redisContext(db), redisTokenKey(jti)).Result()
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return parseRedisToken(values, dto)
+}
+
+// Отсутствующие токены пропускаются, как и в выборке из Postgres
+func (rs *RedisTokenStore) GetTokensByJTIs(db *gorm.DB, jtis []string, dtos *[]dto.TokenDto) error {
+	ctx := redisContext(db)
+
+	pipe := rs.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(jtis))
+	for _, jti := range jtis {
+		cmds = append(cmds, pipe.HGetAll(ctx, redisTokenKey(jti)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	for _, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) == 0 {
+			continue
+		}
+
+		var tokenDto dto.TokenDto
+		if err := parseRedisToken(values, &tokenDto); err != nil {
+			return err
+		}
+		*dtos = append(*dtos, tokenDto)
+	}
+	return nil
+}
+
+func parseRedisToken(values map[string]string, dto *dto.TokenDto) error {
+	subjectId, err := strconv.ParseInt(values["subject_id"], 10, 64)
+	if err != nil {
+		return err
+	}
+
+	dto.JTI = values["jti"]
+	dto.SubjectId = subjectId
+	dto.TokenType = values["token_type"]
+	dto.Revoked = values["revoked"] == "1"
+	dto.IssuedAt = parseRedisTimestamp(values["issued_at"])
+	dto.ExpiresAt = parseRedisTimestamp(values["expires_at"])
+	dto.FamilyId = values["family_id"]
+	dto.Rotated = values["rotated"] == "1"
+	dto.RevokedAt = parseRedisTimestamp(values["revoked_at"])
+	return nil
+}
+
+func (rs *RedisTokenStore) GetTokenByJTIForUpdate(db *gorm.DB, jti string, dto *dto.TokenDto) error {
+	return rs.GetTokenByJTI(db, jti, dto)
+}
+
+func (rs *RedisTokenStore) revoke(ctx context.Context, jtis []string) error {
+	if len(jtis) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(jtis)+1)
+	keys = append(keys, redisRevokedAccessKey)
+	for _, jti := range jtis {
+		keys = append(keys, redisTokenKey(jti))
+	}
+
+	now := time.Now()
+	return redisRevokeScript.Run(ctx, rs.client, keys, now.UnixMicro(), now.Add(-rs.ttl).UnixMicro()).Err()
+}
+
+func (rs *RedisTokenStore) RevokeAllTokensWithBySubjectId(db *gorm.DB, subjectId int64) error {
+	ctx := redisContext(db)
+	jtis, err := rs.client.SMembers(ctx, redisSubjectKey(subjectId)).Result()
+	if err != nil {
+		return err
+	}
+	return rs.revoke(ctx, jtis)
+}
+
+func (rs *RedisTokenStore) RevokeAllTokensBySubjectIdExceptFamilyId(db *gorm.DB, subjectId int64, familyId string) error {
+	ctx := redisContext(db)
+	jtis, err := rs.client.SDiff(ctx, redisSubjectKey(subjectId), redisFamilyKey(familyId)).Result()
+	if err != nil {
+		return err
+	}
+	return rs.revoke(ctx, jtis)
+}
+
+func (rs *RedisTokenStore) RevokeTokenByJTI(db *gorm.DB, jti string) error {
+	return rs.revoke(redisContext(db), []string{jti})
+}
+
+func (rs *RedisTokenStore) RevokeAllTokensByFamilyId(db *gorm.DB, familyId string) error {
+	ctx := redisContext(db)
+	jtis, err := rs.client.SMembers(ctx, redisFamilyKey(familyId)).Result()
+	if err != nil {
+		return err
+	}
+	return rs.revoke(ctx, jtis)
+}
+
+func (rs *RedisTokenStore) MarkRotatedByJTI(db *gorm.DB, jti string) error {
+	res, err := redisMarkRotatedScript.Run(redisContext(db), rs.client, []string{redisTokenKey(jti)}).Int()
+	if err != nil {
+		return err
+	}
+	if res == -1 {
+		return gorm.ErrRecordNotFound
+	}
+	if res == 0 {
+		return ErrTokenAlreadyRotated
+	}
+	return nil
+}
+
+func (rs *RedisTokenStore) GetRevokedAccessTokensSince(db *gorm.DB, since time.Time, dtos *[]dto.TokenDto) error {
+	revoked, err := rs.client.ZRangeByScoreWithScores(redisContext(db), redisRevokedAccessKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(since.UnixMicro(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, z := range revoked {
+		jti, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		*dtos = append(*dtos, dto.TokenDto{
+			JTI:       jti,
+			TokenType: "access",
+			Revoked:   true,
+			RevokedAt: pgtype.Timestamp{Time: time.UnixMicro(int64(z.Score)).UTC(), Valid: true},
+		})
+	}
+	return nil
+}
+
+func (rs *RedisTokenStore) GetActiveFamilyIdsBySubjectId(db *gorm.DB, subjectId int64) ([]string, error) {
+	ctx := redisContext(db)
+	jtis, err := rs.client.SMembers(ctx, redisSubjectKey(subjectId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := rs.client.Pipeline()
+	cmds := make([]*redis.SliceCmd, 0, len(jtis))
+	for _, jti := range jtis {
+		cmds = append(cmds, pipe.HMGet(ctx, redisTokenKey(jti), "token_type", "revoked", "family_id"))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	familyIds := make([]string, 0)
+	seen := make(map[string]struct{})
+	expired := make([]any, 0)
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		if values[0] == nil {
+			expired = append(expired, jtis[i])
+			continue
+		}
+		if values[0] != "refresh" || values[1] != "0" {
+			continue
+		}
+
+		familyId, _ := values[2].(string)
+		if _, ok := seen[familyId]; ok {
+			continue
+		}
+		seen[familyId] = struct{}{}
+		familyIds = append(familyIds, familyId)
+	}
+
+	// Истекшие по TTL токены убираем из индекса
+	if len(expired) > 0 {
+		if err := rs.client.SRem(ctx, redisSubjectKey(subjectId), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return familyIds, nil
+}
+
+// Записи о токенах удаляются по TTL, отдельная очистка не нужна
+func (rs *RedisTokenStore) DeleteExpiredTokens(db *gorm.DB, now time.Time, revokedBefore time.Time, limit int) (int64, error) {
+	return 0, nil
+}
+
+// Токены истекшей сессии к этому моменту уже удалены по TTL
+func (rs *RedisTokenStore) DeleteTokensByFamilyIds(db *gorm.DB, familyIds []string) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"AuthService/internal/dto"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Postgres версия контракта запускается только при заданном TEST_DATABASE_DSN,
+// схема должна быть накатена миграциями из db/migrations
+const testDatabaseDSNName = "TEST_DATABASE_DSN"
+
+// tokenStoreFixture - хранилище на время одного теста. advance сдвигает время хранилища
+// для проверки истечения токенов, subject создает пользователя, на которого выдаются токены,
+// session - сессию пользователя, id которой служит family_id токенов.
+type tokenStoreFixture struct {
+	store   TokenStore
+	db      *gorm.DB
+	advance func(d time.Duration)
+	subject func(t *testing.T) int64
+	session func(t *testing.T, subjectId int64) string
+}
+
+func TestPostgresTokenStoreContract(t *testing.T) {
+	dsn := os.Getenv(testDatabaseDSNName)
+	if len(dsn) == 0 {
+		t.Skipf("%s is not set", testDatabaseDSNName)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+
+	runTokenStoreContract(t, func(t *testing.T) tokenStoreFixture {
+		tx := db.Begin()
+		t.Cleanup(func() { tx.Rollback() })
+
+		return tokenStoreFixture{
+			store:   NewPostgresTokenStore(),
+			db:      tx,
+			advance: func(time.Duration) {},
+			subject: func(t *testing.T) int64 {
+				credentials := dto.CredentialsDto{Email: uuid.New().String() + "@example.com", Password: "hash"}
+				if err := tx.Create(&credentials).Error; err != nil {
+					t.Fatalf("create credentials: %v", err)
+				}
+				return credentials.ID
+			},
+			session: func(t *testing.T, subjectId int64) string {
+				now := time.Now().UTC()
+				session := dto.SessionDto{
+					ID:          uuid.New().String(),
+					SubjectId:   subjectId,
+					CreatedAt:   now,
+					LastUsedAt:  now,
+					ExpiresAt:   now.Add(time.Hour),
+					LoginMethod: "password",
+				}
+				if err := tx.Create(&session).Error; err != nil {
+					t.Fatalf("create session: %v", err)
+				}
+				return session.ID
+			},
+		}
+	})
+}
+
+func TestRedisTokenStoreContract(t *testing.T) {
+	runTokenStoreContract(t, func(t *testing.T) tokenStoreFixture {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+
+		var lastSubject int64
+		return tokenStoreFixture{
+			store:   NewRedisTokenStore(client, 24*time.Hour),
+			advance: mr.FastForward,
+			subject: func(t *testing.T) int64 {
+				lastSubject++
+				return lastSubject
+			},
+			session: func(t *testing.T, subjectId int64) string {
+				return uuid.New().String()
+			},
+		}
+	})
+}
+
+func runTokenStoreContract(t *testing.T, newFixture func(t *testing.T) tokenStoreFixture) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		token := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+
+		got := new(dto.TokenDto)
+		if err := f.store.GetTokenByJTI(f.db, token.JTI, got); err != nil {
+			t.Fatalf("GetTokenByJTI: %v", err)
+		}
+		if got.JTI != token.JTI || got.SubjectId != subjectId || got.TokenType != "refresh" || got.FamilyId != token.FamilyId {
+			t.Fatalf("GetTokenByJTI = %+v, want %+v", got, token)
+		}
+		if got.Revoked || got.Rotated || got.RevokedAt.Valid {
+			t.Fatalf("new token must not be revoked or rotated: %+v", got)
+		}
+		if !got.ExpiresAt.Time.Equal(token.ExpiresAt.Time) {
+			t.Fatalf("ExpiresAt = %v, want %v", got.ExpiresAt.Time, token.ExpiresAt.Time)
+		}
+
+		locked := new(dto.TokenDto)
+		if err := f.store.GetTokenByJTIForUpdate(f.db, token.JTI, locked); err != nil || locked.JTI != token.JTI {
+			t.Fatalf("GetTokenByJTIForUpdate = %+v, %v", locked, err)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		f := newFixture(t)
+
+		err := f.store.GetTokenByJTI(f.db, uuid.New().String(), new(dto.TokenDto))
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetTokenByJTI error = %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("GetTokensByJTIsSkipsMissing", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		family := f.session(t, subjectId)
+		first := createTestToken(t, f, subjectId, "access", family, time.Hour)
+		second := createTestToken(t, f, subjectId, "refresh", family, time.Hour)
+
+		var tokens []dto.TokenDto
+		if err := f.store.GetTokensByJTIs(f.db, []string{first.JTI, uuid.New().String(), second.JTI}, &tokens); err != nil {
+			t.Fatalf("GetTokensByJTIs: %v", err)
+		}
+		if jtis := tokenJTIs(tokens); !sameStrings(jtis, []string{first.JTI, second.JTI}) {
+			t.Fatalf("GetTokensByJTIs = %v, want %v", jtis, []string{first.JTI, second.JTI})
+		}
+	})
+
+	t.Run("RevokeToken", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		before := time.Now().Add(-time.Minute)
+		token := createTestToken(t, f, subjectId, "access", f.session(t, subjectId), time.Hour)
+
+		if err := f.store.RevokeTokenByJTI(f.db, token.JTI); err != nil {
+			t.Fatalf("RevokeTokenByJTI: %v", err)
+		}
+
+		got := mustGetToken(t, f, token.JTI)
+		if !got.Revoked || !got.RevokedAt.Valid {
+			t.Fatalf("token not revoked: %+v", got)
+		}
+
+		var revoked []dto.TokenDto
+		if err := f.store.GetRevokedAccessTokensSince(f.db, before, &revoked); err != nil {
+			t.Fatalf("GetRevokedAccessTokensSince: %v", err)
+		}
+		if !slices.Contains(tokenJTIs(revoked), token.JTI) {
+			t.Fatalf("GetRevokedAccessTokensSince = %v, want to contain %s", tokenJTIs(revoked), token.JTI)
+		}
+	})
+
+	t.Run("RevokeFamily", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		family := f.session(t, subjectId)
+		access := createTestToken(t, f, subjectId, "access", family, time.Hour)
+		refresh := createTestToken(t, f, subjectId, "refresh", family, time.Hour)
+		other := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+
+		if err := f.store.RevokeAllTokensByFamilyId(f.db, family); err != nil {
+			t.Fatalf("RevokeAllTokensByFamilyId: %v", err)
+		}
+
+		if !mustGetToken(t, f, access.JTI).Revoked || !mustGetToken(t, f, refresh.JTI).Revoked {
+			t.Fatal("family tokens must be revoked")
+		}
+		if mustGetToken(t, f, other.JTI).Revoked {
+			t.Fatal("token of another family must stay active")
+		}
+
+		families, err := f.store.GetActiveFamilyIdsBySubjectId(f.db, subjectId)
+		if err != nil {
+			t.Fatalf("GetActiveFamilyIdsBySubjectId: %v", err)
+		}
+		if !sameStrings(families, []string{other.FamilyId}) {
+			t.Fatalf("GetActiveFamilyIdsBySubjectId = %v, want %v", families, []string{other.FamilyId})
+		}
+	})
+
+	t.Run("RevokeSubject", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		otherSubjectId := f.subject(t)
+		first := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+		second := createTestToken(t, f, subjectId, "access", f.session(t, subjectId), time.Hour)
+		other := createTestToken(t, f, otherSubjectId, "refresh", f.session(t, otherSubjectId), time.Hour)
+
+		if err := f.store.RevokeAllTokensWithBySubjectId(f.db, subjectId); err != nil {
+			t.Fatalf("RevokeAllTokensWithBySubjectId: %v", err)
+		}
+
+		if !mustGetToken(t, f, first.JTI).Revoked || !mustGetToken(t, f, second.JTI).Revoked {
+			t.Fatal("subject tokens must be revoked")
+		}
+		if mustGetToken(t, f, other.JTI).Revoked {
+			t.Fatal("token of another subject must stay active")
+		}
+
+		families, err := f.store.GetActiveFamilyIdsBySubjectId(f.db, subjectId)
+		if err != nil {
+			t.Fatalf("GetActiveFamilyIdsBySubjectId: %v", err)
+		}
+		if len(families) != 0 {
+			t.Fatalf("GetActiveFamilyIdsBySubjectId = %v, want none", families)
+		}
+	})
+
+	t.Run("RevokeSubjectExceptFamily", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		keptFamily := f.session(t, subjectId)
+		kept := createTestToken(t, f, subjectId, "refresh", keptFamily, time.Hour)
+		revoked := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+
+		if err := f.store.RevokeAllTokensBySubjectIdExceptFamilyId(f.db, subjectId, keptFamily); err != nil {
+			t.Fatalf("RevokeAllTokensBySubjectIdExceptFamilyId: %v", err)
+		}
+
+		if mustGetToken(t, f, kept.JTI).Revoked {
+			t.Fatal("token of the kept family must stay active")
+		}
+		if !mustGetToken(t, f, revoked.JTI).Revoked {
+			t.Fatal("token of another family must be revoked")
+		}
+	})
+
+	t.Run("MarkRotatedOnce", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		token := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+
+		if err := f.store.MarkRotatedByJTI(f.db, token.JTI); err != nil {
+			t.Fatalf("first MarkRotatedByJTI: %v", err)
+		}
+		if !mustGetToken(t, f, token.JTI).Rotated {
+			t.Fatal("token must be marked rotated")
+		}
+
+		if err := f.store.MarkRotatedByJTI(f.db, token.JTI); !errors.Is(err, ErrTokenAlreadyRotated) {
+			t.Fatalf("second MarkRotatedByJTI error = %v, want ErrTokenAlreadyRotated", err)
+		}
+		if err := f.store.MarkRotatedByJTI(f.db, uuid.New().String()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("MarkRotatedByJTI of missing token error = %v, want gorm.ErrRecordNotFound", err)
+		}
+	})
+
+	t.Run("ExpiredTokensDisappear", func(t *testing.T) {
+		f := newFixture(t)
+		subjectId := f.subject(t)
+		expired := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), -time.Minute)
+		active := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+		revoked := createTestToken(t, f, subjectId, "refresh", f.session(t, subjectId), time.Hour)
+		if err := f.store.RevokeTokenByJTI(f.db, revoked.JTI); err != nil {
+			t.Fatalf("RevokeTokenByJTI: %v", err)
+		}
+
+		f.advance(2 * time.Second)
+		if _, err := f.store.DeleteExpiredTokens(f.db, time.Now().UTC(), time.Now().UTC().Add(-time.Hour), 100); err != nil {
+			t.Fatalf("DeleteExpiredTokens: %v", err)
+		}
+
+		if err := f.store.GetTokenByJTI(f.db, expired.JTI, new(dto.TokenDto)); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("expired token lookup error = %v, want gorm.ErrRecordNotFound", err)
+		}
+		mustGetToken(t, f, active.JTI)
+		// Отозванный токен хранится до истечения, иначе нельзя обнаружить его повторное использование
+		if !mustGetToken(t, f, revoked.JTI).Revoked {
+			t.Fatal("revoked token must be kept until it expires")
+		}
+	})
+}
+
+func createTestToken(t *testing.T, f tokenStoreFixture, subjectId int64, tokenType string, familyId string, lifeTime time.Duration) dto.TokenDto {
+	t.Helper()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	token := dto.TokenDto{
+		JTI:       uuid.New().String(),
+		SubjectId: subjectId,
+		TokenType: tokenType,
+		IssuedAt:  pgtype.Timestamp{Time: now, Valid: true},
+		ExpiresAt: pgtype.Timestamp{Time: now.Add(lifeTime), Valid: true},
+		FamilyId:  familyId,
+	}
+	if err := f.store.Create(f.db, &token); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return token
+}
+
+func mustGetToken(t *testing.T, f tokenStoreFixture, jti string) dto.TokenDto {
+	t.Helper()
+
+	token := new(dto.TokenDto)
+	if err := f.store.GetTokenByJTI(f.db, jti, token); err != nil {
+		t.Fatalf("GetTokenByJTI(%s): %v", jti, err)
+	}
+	return *token
+}
+
+func tokenJTIs(tokens []dto.TokenDto) []string {
+	jtis := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		jtis = append(jtis, token.JTI)
+	}
+	return jtis
+}
+
+func sameStrings(a []string, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -1,30 +1,43 @@
 package service
 
 import (
+	"AuthService/internal/config"
 	"AuthService/internal/convertor"
 	"AuthService/internal/dto"
 	"AuthService/internal/entity"
 	"AuthService/internal/external"
+	"AuthService/internal/utils"
 	"context"
+	"errors"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
-	"math/rand"
 )
 
+// Хэш случайного пароля с той же стоимостью, что и в HashPassword. С ним сравнивается пароль
+// для несуществующей почты, чтобы время ответа не выдавало, зарегистрирован ли аккаунт.
+const dummyPasswordHash = "$2a$14$RjHC3hkcObvszGuFoN1sCe97lGKvKvzwuhZlH/KQsXPG1GvAhCX1G"
+
 type CredentialsService struct {
-	db        *gorm.DB
-	crRepo    credentialsRepository
-	tokenRepo tokensRepository
-	external  *external.NotificationExternal
+	db                *gorm.DB
+	crRepo            credentialsRepository
+	tokenRepo         tokensRepository
+	confirmationCodes *OneTimeCodeService
+	resetRepo         passwordResetTokensRepository
+	external          *external.NotificationExternal
+	resetConfig       *config.PasswordResetConfig
 }
 
-func NewCredentialsService(db *gorm.DB, crRepo credentialsRepository, tokensRepo tokensRepository, external *external.NotificationExternal) *CredentialsService {
+func NewCredentialsService(db *gorm.DB, crRepo credentialsRepository, tokensRepo tokensRepository, confirmationCodes *OneTimeCodeService, resetRepo passwordResetTokensRepository, external *external.NotificationExternal, resetConfig *config.PasswordResetConfig) *CredentialsService {
 	return &CredentialsService{
-		db:        db,
-		crRepo:    crRepo,
-		tokenRepo: tokensRepo,
-		external:  external,
+		db:                db,
+		crRepo:            crRepo,
+		tokenRepo:         tokensRepo,
+		confirmationCodes: confirmationCodes,
+		resetRepo:         resetRepo,
+		external:          external,
+		resetConfig:       resetConfig,
 	}
 }
 
@@ -54,6 +67,10 @@ func (cr *CredentialsService) ValidatePassword(password, hash string) bool {
 	return err == nil
 }
 
+func (cr *CredentialsService) ValidateDummyPassword(password string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+}
+
 func (cr *CredentialsService) CreateCredentials(ctx context.Context, credentials entity.Credentials) error {
 	tx := cr.db.WithContext(ctx).Begin()
 	defer tx.Rollback()
@@ -95,17 +112,7 @@ func (cr *CredentialsService) GetCredentialsById(ctx context.Context, id int64)
 	return credentialsDto.ToCredentialsEntity(), nil
 }
 
-func generateRandomCode() string {
-	letterBytes := "0123456789"
-	b := make([]byte, 6)
-	for i := range b {
-		b[i] = letterBytes[rand.Intn(len(letterBytes))]
-	}
-	return string(b)
-}
-
-func (cr *CredentialsService) SendConfirmRegistrationMailToEmail(email string) (string, error) {
-	code := generateRandomCode()
+func (cr *CredentialsService) SendConfirmRegistrationMailToEmail(email string, code string) error {
 	req := entity.EmailEventNotificationEntity{
 		Name:  "Подтверждение регистрации",
 		Title: "Подтвердите регистрацию",
@@ -113,10 +120,238 @@ func (cr *CredentialsService) SendConfirmRegistrationMailToEmail(email string) (
 		Email: email,
 	}
 
-	if err := cr.external.SendEmailEventNotification(&req); err != nil {
+	return cr.external.SendEmailEventNotification(&req)
+}
+
+// CreateConfirmationCode сохраняет хэш нового кода подтверждения и возвращает сам код для письма.
+// Код сохраняется до отправки, чтобы пользователь не получил код, который нельзя подтвердить.
+func (cr *CredentialsService) CreateConfirmationCode(ctx context.Context, email string) (string, error) {
+	tx := cr.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := cr.crRepo.GetByEmail(tx, email, credentialsDto); err != nil {
+		return "", err
+	}
+
+	code, err := cr.confirmationCodes.Issue(tx, credentialsDto.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return "", err
 	}
 
 	return code, nil
 }
 
+// ResendConfirmationCode выдает новый код подтверждения, если почта не подтверждена и не превышены
+// пауза между отправками и дневной лимит; иначе возвращает пустую строку.
+// Строка пользователя блокируется, поэтому параллельные запросы не проходят проверку лимитов вместе.
+func (cr *CredentialsService) ResendConfirmationCode(ctx context.Context, email string) (string, error) {
+	tx := cr.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := cr.crRepo.GetByEmailForUpdate(tx, email, credentialsDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if credentialsDto.EmailVerified {
+		return "", nil
+	}
+
+	code, err := cr.confirmationCodes.IssueLimited(tx, credentialsDto.ID)
+	if err != nil || len(code) == 0 {
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (cr *CredentialsService) ConfirmEmail(ctx context.Context, email string, code string) error {
+	tx := cr.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// Любая неудача - неизвестная или уже подтвержденная почта, истекший код, исчерпанные попытки -
+	// дает ту же ошибку, что и неверный код, чтобы по ответу нельзя было проверить существование аккаунта
+	credentialsDto := new(dto.CredentialsDto)
+	if err := cr.crRepo.GetByEmail(tx, email, credentialsDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.InvalidConfirmationCode
+		}
+		return err
+	}
+
+	if credentialsDto.EmailVerified {
+		return utils.InvalidConfirmationCode
+	}
+
+	if err := cr.confirmationCodes.Verify(tx, credentialsDto.ID, code); err != nil {
+		return rejectOneTimeCode(tx, err, utils.InvalidConfirmationCode)
+	}
+
+	credentialsDto.EmailVerified = true
+	if err := cr.crRepo.Update(tx, credentialsDto); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (cr *CredentialsService) CreatePasswordResetToken(ctx context.Context, email string) (string, error) {
+	tx := cr.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := cr.crRepo.GetByEmail(tx, email, credentialsDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if err := cr.resetRepo.InvalidateAllByCredentialsId(tx, credentialsDto.ID); err != nil {
+		return "", err
+	}
+
+	token, err := generateOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	tokenDto := dto.PasswordResetTokenDto{
+		CredentialsId: credentialsDto.ID,
+		TokenHash:     hashOneTimeSecret(token),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(cr.resetConfig.TokenLifeTime),
+	}
+
+	if err := cr.resetRepo.Create(tx, &tokenDto); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (cr *CredentialsService) SendSecurityAlertMailToEmail(email string, body string) error {
+	req := entity.EmailEventNotificationEntity{
+		Name:  "Предупреждение безопасности",
+		Title: "Подозрительная активность в аккаунте",
+		Body:  body,
+		Email: email,
+	}
+
+	return cr.external.SendEmailEventNotification(&req)
+}
+
+func (cr *CredentialsService) SendLoginLockedMailToEmail(email string, lockedUntil time.Time) error {
+	req := entity.EmailEventNotificationEntity{
+		Name:  "Вход заблокирован",
+		Title: "Вход в аккаунт временно заблокирован",
+		Body: "Из-за нескольких неудачных попыток входа вход в аккаунт заблокирован до " + lockedUntil.Format("02.01.2006 15:04 MST") +
+			". После этого войти можно будет как обычно.\nЕсли это были не вы, рекомендуем сменить пароль через сброс пароля.",
+		Email: email,
+	}
+
+	return cr.external.SendEmailEventNotification(&req)
+}
+
+func (cr *CredentialsService) SendRegistrationAttemptMailToEmail(email string) error {
+	req := entity.EmailEventNotificationEntity{
+		Name:  "Попытка регистрации",
+		Title: "Попытка повторной регистрации",
+		Body: "Кто-то попытался зарегистрироваться с этой почтой, но аккаунт с ней уже существует.\n" +
+			"Если это были вы, просто войдите в аккаунт или восстановите пароль через сброс пароля. Если нет, проигнорируйте это письмо.",
+		Email: email,
+	}
+
+	return cr.external.SendEmailEventNotification(&req)
+}
+
+func (cr *CredentialsService) SendPasswordResetMailToEmail(email string, token string) error {
+	req := entity.EmailEventNotificationEntity{
+		Name:  "Сброс пароля",
+		Title: "Сброс пароля",
+		Body:  "Токен для сброса пароля: " + token + ".\nЕсли вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+		Email: email,
+	}
+
+	return cr.external.SendEmailEventNotification(&req)
+}
+
+func (cr *CredentialsService) ResetPasswordByToken(ctx context.Context, token string, passwordHash string) error {
+	tx := cr.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	tokenDto := new(dto.PasswordResetTokenDto)
+	if err := cr.resetRepo.GetActiveByTokenHash(tx, hashOneTimeSecret(token), tokenDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.InvalidResetToken
+		}
+		return err
+	}
+
+	if time.Now().After(tokenDto.ExpiresAt) {
+		return utils.InvalidResetToken
+	}
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := cr.crRepo.GetById(tx, credentialsDto, tokenDto.CredentialsId); err != nil {
+		return err
+	}
+
+	// Письмо со сбросом получено, значит почта принадлежит пользователю
+	credentialsDto.Password = passwordHash
+	credentialsDto.EmailVerified = true
+	if err := cr.crRepo.Update(tx, credentialsDto); err != nil {
+		return err
+	}
+
+	tokenDto.Used = true
+	if err := cr.resetRepo.Update(tx, tokenDto); err != nil {
+		return err
+	}
+
+	if err := cr.tokenRepo.RevokeAllTokensWithBySubjectId(tx, credentialsDto.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UpdatePassword меняет пароль и отзывает все сессии пользователя.
+// Смена пароля с сохранением текущей сессии - TokensService.UpdatePasswordKeepingSession.
+func (cr *CredentialsService) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	tx := cr.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := cr.crRepo.GetById(tx, credentialsDto, id); err != nil {
+		return err
+	}
+
+	credentialsDto.Password = passwordHash
+	if err := cr.crRepo.Update(tx, credentialsDto); err != nil {
+		return err
+	}
+
+	if err := cr.tokenRepo.RevokeAllTokensWithBySubjectId(tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
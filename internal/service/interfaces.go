@@ -3,6 +3,7 @@ package service
 import (
 	"AuthService/internal/dto"
 	"gorm.io/gorm"
+	"time"
 )
 
 type credentialsRepository interface {
@@ -13,15 +14,73 @@ type credentialsRepository interface {
 	GetCountById(db *gorm.DB, id any) (int64, error)
 	GetCountByEmail(db *gorm.DB, email string) (int64, error)
 	GetByEmail(db *gorm.DB, email string, entity *dto.CredentialsDto) error
+	GetByEmailForUpdate(db *gorm.DB, email string, entity *dto.CredentialsDto) error
 }
 
 type tokensRepository interface {
-	GetById(db *gorm.DB, dto *dto.TokenDto, id any) error
 	Create(db *gorm.DB, dto *dto.TokenDto) error
-	Update(db *gorm.DB, dto *dto.TokenDto) error
-	Delete(db *gorm.DB, dto *dto.TokenDto) error
-	GetCountById(db *gorm.DB, id any) (int64, error)
+	GetTokensByJTIs(db *gorm.DB, jtis []string, dtos *[]dto.TokenDto) error
 	GetTokenByJTI(db *gorm.DB, jti string, entity *dto.TokenDto) error
 	RevokeAllTokensWithBySubjectId(db *gorm.DB, subjectId int64) error
+	RevokeAllTokensBySubjectIdExceptFamilyId(db *gorm.DB, subjectId int64, familyId string) error
 	RevokeTokenByJTI(db *gorm.DB, jti string) error
+	GetTokenByJTIForUpdate(db *gorm.DB, jti string, entity *dto.TokenDto) error
+	RevokeAllTokensByFamilyId(db *gorm.DB, familyId string) error
+	MarkRotatedByJTI(db *gorm.DB, jti string) error
+	GetRevokedAccessTokensSince(db *gorm.DB, since time.Time, dtos *[]dto.TokenDto) error
+	GetActiveFamilyIdsBySubjectId(db *gorm.DB, subjectId int64) ([]string, error)
+	DeleteExpiredTokens(db *gorm.DB, now time.Time, revokedBefore time.Time, limit int) (int64, error)
+	DeleteTokensByFamilyIds(db *gorm.DB, familyIds []string) (int64, error)
+}
+
+type oneTimeCodesRepository interface {
+	Create(db *gorm.DB, dto *dto.OneTimeCodeDto) error
+	Update(db *gorm.DB, dto *dto.OneTimeCodeDto) error
+	GetLastActiveByCredentialsId(db *gorm.DB, credentialsId int64, dto *dto.OneTimeCodeDto) error
+	InvalidateAllByCredentialsId(db *gorm.DB, credentialsId int64) error
+	GetLastByCredentialsId(db *gorm.DB, credentialsId int64, dto *dto.OneTimeCodeDto) error
+	GetCountCreatedSince(db *gorm.DB, credentialsId int64, since time.Time) (int64, error)
+}
+
+type passwordResetTokensRepository interface {
+	Create(db *gorm.DB, dto *dto.PasswordResetTokenDto) error
+	Update(db *gorm.DB, dto *dto.PasswordResetTokenDto) error
+	GetActiveByTokenHash(db *gorm.DB, tokenHash string, dto *dto.PasswordResetTokenDto) error
+	InvalidateAllByCredentialsId(db *gorm.DB, credentialsId int64) error
+}
+
+type loginAttemptsRepository interface {
+	Update(db *gorm.DB, dto *dto.LoginAttemptDto) error
+	GetByKeyForUpdate(db *gorm.DB, key string, dto *dto.LoginAttemptDto) error
+	DeleteByKey(db *gorm.DB, key string) error
+	DeleteStale(db *gorm.DB, now time.Time, before time.Time, limit int) (int64, error)
+}
+
+type sessionsRepository interface {
+	Create(db *gorm.DB, dto *dto.SessionDto) error
+	GetActiveBySubjectIdAndIds(db *gorm.DB, subjectId int64, ids []string, now time.Time, dtos *[]dto.SessionDto) error
+	GetBySubjectIdAndId(db *gorm.DB, subjectId int64, id string, dto *dto.SessionDto) error
+	Touch(db *gorm.DB, id string, userAgent string, ip string, lastUsedAt time.Time, expiresAt time.Time) error
+	GetExpiredIdsForUpdate(db *gorm.DB, now time.Time, limit int) ([]string, error)
+	DeleteByIds(db *gorm.DB, ids []string) (int64, error)
+}
+
+type totpRepository interface {
+	Create(db *gorm.DB, dto *dto.TOTPDto) error
+	Update(db *gorm.DB, dto *dto.TOTPDto) error
+	GetByCredentialsId(db *gorm.DB, credentialsId int64, dto *dto.TOTPDto) error
+	GetByCredentialsIdForUpdate(db *gorm.DB, credentialsId int64, dto *dto.TOTPDto) error
+	GetConfirmedCount(db *gorm.DB) (int64, error)
+}
+
+type recoveryCodesRepository interface {
+	CreateBatch(db *gorm.DB, dtos []dto.RecoveryCodeDto) error
+	DeleteAllByCredentialsId(db *gorm.DB, credentialsId int64) error
+	UseByCodeHash(db *gorm.DB, credentialsId int64, codeHash string) (int64, error)
+}
+
+type mfaChallengesRepository interface {
+	Create(db *gorm.DB, dto *dto.MFAChallengeDto) error
+	Update(db *gorm.DB, dto *dto.MFAChallengeDto) error
+	GetActiveByTokenHash(db *gorm.DB, tokenHash string, dto *dto.MFAChallengeDto) error
 }
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/dto"
+	"AuthService/internal/entity"
+	"AuthService/internal/external"
+	"AuthService/internal/utils"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// LoginCodeService - вход без пароля по одноразовому коду из письма
+type LoginCodeService struct {
+	db       *gorm.DB
+	crRepo   credentialsRepository
+	codes    *OneTimeCodeService
+	external *external.NotificationExternal
+	cfg      *config.LoginCodeConfig
+}
+
+func NewLoginCodeService(db *gorm.DB, crRepo credentialsRepository, codes *OneTimeCodeService, external *external.NotificationExternal, cfg *config.LoginCodeConfig) *LoginCodeService {
+	return &LoginCodeService{
+		db:       db,
+		crRepo:   crRepo,
+		codes:    codes,
+		external: external,
+		cfg:      cfg,
+	}
+}
+
+// CreateLoginCode возвращает пустой код, если аккаунта нет или лимит отправки исчерпан:
+// клиент в любом случае получает одинаковый ответ
+func (ls *LoginCodeService) CreateLoginCode(ctx context.Context, email string) (string, error) {
+	tx := ls.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := ls.crRepo.GetByEmailForUpdate(tx, email, credentialsDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	code, err := ls.codes.IssueLimited(tx, credentialsDto.ID)
+	if err != nil || len(code) == 0 {
+		return "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (ls *LoginCodeService) SendLoginCodeMailToEmail(email string, code string) error {
+	req := entity.EmailEventNotificationEntity{
+		Name:  "Код входа",
+		Title: "Код для входа в аккаунт",
+		Body:  "Код для входа: " + code + ". Код действует " + ls.cfg.CodeLifeTime.String() + ".\nНикому не сообщайте код. Если вы не запрашивали вход, просто проигнорируйте это письмо.",
+		Email: email,
+	}
+
+	return ls.external.SendEmailEventNotification(&req)
+}
+
+// VerifyLoginCode гасит код и возвращает пользователя. Код пришел на почту, поэтому она считается подтвержденной.
+func (ls *LoginCodeService) VerifyLoginCode(ctx context.Context, email string, code string) (entity.Credentials, error) {
+	tx := ls.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := ls.crRepo.GetByEmail(tx, email, credentialsDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Credentials{}, utils.InvalidLoginCode
+		}
+		return entity.Credentials{}, err
+	}
+
+	if err := ls.codes.Verify(tx, credentialsDto.ID, code); err != nil {
+		if errors.Is(err, errOneTimeCodeAttemptsExceeded) {
+			return entity.Credentials{}, rejectOneTimeCode(tx, err, utils.TooManyLoginCodeAttempts)
+		}
+		return entity.Credentials{}, rejectOneTimeCode(tx, err, utils.InvalidLoginCode)
+	}
+
+	if !credentialsDto.EmailVerified {
+		credentialsDto.EmailVerified = true
+		if err := ls.crRepo.Update(tx, credentialsDto); err != nil {
+			return entity.Credentials{}, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return entity.Credentials{}, err
+	}
+
+	return credentialsDto.ToCredentialsEntity(), nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/dto"
+	"AuthService/internal/metrics"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/gorm"
+)
+
+// LoginThrottleService считает неудачные попытки входа отдельно по почте и по IP адресу клиента.
+// Для почты после BackoffThreshold неудач подряд вход запрещается на экспоненциально растущий интервал,
+// после LockoutThreshold - на LockoutDuration. Для IP действует только блокировка с собственным порогом.
+type LoginThrottleService struct {
+	db     *gorm.DB
+	laRepo loginAttemptsRepository
+	cfg    *config.LoginProtectionConfig
+}
+
+func NewLoginThrottleService(db *gorm.DB, laRepo loginAttemptsRepository, cfg *config.LoginProtectionConfig) *LoginThrottleService {
+	metrics.SetLoginProtectionConfig("backoff_threshold", float64(cfg.BackoffThreshold))
+	metrics.SetLoginProtectionConfig("backoff_base_seconds", cfg.BackoffBase.Seconds())
+	metrics.SetLoginProtectionConfig("lockout_threshold", float64(cfg.LockoutThreshold))
+	metrics.SetLoginProtectionConfig("ip_lockout_threshold", float64(cfg.IPLockoutThreshold))
+	metrics.SetLoginProtectionConfig("lockout_duration_seconds", cfg.LockoutDuration.Seconds())
+	metrics.SetLoginProtectionConfig("failure_window_seconds", cfg.FailureWindow.Seconds())
+
+	return &LoginThrottleService{
+		db:     db,
+		laRepo: laRepo,
+		cfg:    cfg,
+	}
+}
+
+func loginAccountKey(email string) string {
+	return "email:" + email
+}
+
+func loginIPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Acquire учитывает попытку входа до проверки пароля: строки счетчиков почты и IP блокируются на время
+// транзакции, поэтому параллельные попытки не обходят порог. Если вход запрещен, попытка не учитывается
+// и возвращается оставшееся время запрета. accountLocked сообщает, что эта попытка заблокировала почту до lockedUntil.
+func (lt *LoginThrottleService) Acquire(ctx context.Context, email string, ip string) (time.Duration, bool, time.Time, error) {
+	tx := lt.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	// Строки блокируются всегда в одном порядке: сначала почта, затем IP
+	accountAttempt := new(dto.LoginAttemptDto)
+	if err := lt.laRepo.GetByKeyForUpdate(tx, loginAccountKey(email), accountAttempt); err != nil {
+		return 0, false, time.Time{}, err
+	}
+
+	var ipAttempt *dto.LoginAttemptDto
+	if len(ip) > 0 {
+		ipAttempt = new(dto.LoginAttemptDto)
+		if err := lt.laRepo.GetByKeyForUpdate(tx, loginIPKey(ip), ipAttempt); err != nil {
+			return 0, false, time.Time{}, err
+		}
+	}
+
+	retryAfter := lockedFor(accountAttempt, now)
+	if ipAttempt != nil {
+		retryAfter = max(retryAfter, lockedFor(ipAttempt, now))
+	}
+	if retryAfter > 0 {
+		return retryAfter, false, time.Time{}, nil
+	}
+
+	accountLocked, lockedUntil, err := lt.registerFailure(tx, accountAttempt, true, lt.cfg.LockoutThreshold, now)
+	if err != nil {
+		return 0, false, time.Time{}, err
+	}
+	if accountLocked {
+		metrics.IncLoginLockout(metrics.LoginScopeAccount)
+	}
+
+	if ipAttempt != nil {
+		ipLocked, _, err := lt.registerFailure(tx, ipAttempt, false, lt.cfg.IPLockoutThreshold, now)
+		if err != nil {
+			return 0, false, time.Time{}, err
+		}
+		if ipLocked {
+			metrics.IncLoginLockout(metrics.LoginScopeIP)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, false, time.Time{}, err
+	}
+
+	return 0, accountLocked, lockedUntil, nil
+}
+
+func lockedFor(attemptDto *dto.LoginAttemptDto, now time.Time) time.Duration {
+	if attemptDto.LockedUntil.Valid && attemptDto.LockedUntil.Time.After(now) {
+		return attemptDto.LockedUntil.Time.Sub(now)
+	}
+
+	return 0
+}
+
+func (lt *LoginThrottleService) registerFailure(tx *gorm.DB, attemptDto *dto.LoginAttemptDto, backoff bool, lockoutThreshold int, now time.Time) (bool, time.Time, error) {
+	if attemptDto.LastFailureAt.Valid && now.Sub(attemptDto.LastFailureAt.Time) > lt.cfg.FailureWindow {
+		attemptDto.Failures = 0
+	}
+	attemptDto.Failures++
+	attemptDto.LastFailureAt = pgtype.Timestamp{Time: now, Valid: true}
+
+	var (
+		locked bool
+		delay  time.Duration
+	)
+	switch {
+	case attemptDto.Failures >= lockoutThreshold:
+		locked = true
+		delay = lt.cfg.LockoutDuration
+	case backoff && attemptDto.Failures >= lt.cfg.BackoffThreshold:
+		delay = lt.backoffDelay(attemptDto.Failures - lt.cfg.BackoffThreshold)
+	}
+
+	lockedUntil := now.Add(delay)
+	if delay > 0 {
+		attemptDto.LockedUntil = pgtype.Timestamp{Time: lockedUntil, Valid: true}
+	}
+
+	if err := lt.laRepo.Update(tx, attemptDto); err != nil {
+		return false, time.Time{}, err
+	}
+
+	return locked, lockedUntil, nil
+}
+
+// Задержка удваивается с каждой неудачей и не превышает длительности блокировки
+func (lt *LoginThrottleService) backoffDelay(step int) time.Duration {
+	delay := lt.cfg.BackoffBase
+	for i := 0; i < step && delay < lt.cfg.LockoutDuration; i++ {
+		delay *= 2
+	}
+
+	return min(delay, lt.cfg.LockoutDuration)
+}
+
+// Succeed вызывается после успешного входа: счетчик почты сбрасывается, а со счетчика IP снимается только
+// попытка, учтенная Acquire. Полностью счетчик IP не сбрасывается, чтобы вход в собственный аккаунт
+// не обнулял перебор чужих.
+func (lt *LoginThrottleService) Succeed(ctx context.Context, email string, ip string) error {
+	tx := lt.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := lt.laRepo.DeleteByKey(tx, loginAccountKey(email)); err != nil {
+		return err
+	}
+
+	if len(ip) > 0 {
+		ipAttempt := new(dto.LoginAttemptDto)
+		if err := lt.laRepo.GetByKeyForUpdate(tx, loginIPKey(ip), ipAttempt); err != nil {
+			return err
+		}
+
+		if ipAttempt.Failures > 0 {
+			ipAttempt.Failures--
+			if ipAttempt.Failures < lt.cfg.IPLockoutThreshold {
+				ipAttempt.LockedUntil = pgtype.Timestamp{}
+			}
+
+			if err := lt.laRepo.Update(tx, ipAttempt); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit().Error
+}
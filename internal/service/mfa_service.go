@@ -0,0 +1,355 @@
+package service
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/dto"
+	"AuthService/internal/entity"
+	"AuthService/internal/mfa"
+	"AuthService/internal/utils"
+	"context"
+	cryptoRand "crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"gorm.io/gorm"
+)
+
+// Код восстановления - 10 символов base32, выдается пользователю в виде XXXXX-XXXXX
+const recoveryCodeSize = 10
+
+type MFAService struct {
+	db             *gorm.DB
+	totpRepo       totpRepository
+	recoveryRepo   recoveryCodesRepository
+	challengesRepo mfaChallengesRepository
+	// nil, если ключ шифрования не задан
+	cipher *mfa.SecretCipher
+	cfg    *config.MFAConfig
+}
+
+func NewMFAService(db *gorm.DB, totpRepo totpRepository, recoveryRepo recoveryCodesRepository, challengesRepo mfaChallengesRepository, cipher *mfa.SecretCipher, cfg *config.MFAConfig) *MFAService {
+	return &MFAService{
+		db:             db,
+		totpRepo:       totpRepo,
+		recoveryRepo:   recoveryRepo,
+		challengesRepo: challengesRepo,
+		cipher:         cipher,
+		cfg:            cfg,
+	}
+}
+
+// CheckConfigured не дает запуститься без ключа шифрования, если у пользователей уже подключен TOTP:
+// иначе такие пользователи не смогли бы пройти второй фактор и войти.
+func (ms *MFAService) CheckConfigured(ctx context.Context) error {
+	if ms.cipher != nil {
+		return nil
+	}
+
+	cnt, err := ms.totpRepo.GetConfirmedCount(ms.db.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if cnt > 0 {
+		return fmt.Errorf("%d users have confirmed TOTP, but MFA_ENCRYPTION_KEY is not set", cnt)
+	}
+
+	return nil
+}
+
+func secretAssociatedData(credentialsId int64) []byte {
+	return []byte(strconv.FormatInt(credentialsId, 10))
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToUpper(code)
+}
+
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	if _, err := cryptoRand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := base32.StdEncoding.EncodeToString(b)[:recoveryCodeSize]
+	return code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:], nil
+}
+
+// EnrollTOTP выдает новый секрет; пока он не подтвержден кодом, вход по-прежнему однофакторный
+func (ms *MFAService) EnrollTOTP(ctx context.Context, credentials entity.Credentials) (string, string, error) {
+	if ms.cipher == nil {
+		return "", "", utils.MFANotConfigured
+	}
+
+	tx := ms.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	totpDto := new(dto.TOTPDto)
+	exists := true
+	if err := ms.totpRepo.GetByCredentialsIdForUpdate(tx, credentials.ID, totpDto); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", err
+		}
+		exists = false
+	}
+	if exists && totpDto.ConfirmedAt.Valid {
+		return "", "", utils.TOTPAlreadyEnabled
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	encrypted, err := ms.cipher.Encrypt(secret, secretAssociatedData(credentials.ID))
+	if err != nil {
+		return "", "", err
+	}
+
+	totpDto.CredentialsId = credentials.ID
+	totpDto.SecretEncrypted = encrypted
+	totpDto.LastUsedStep = 0
+	if exists {
+		err = ms.totpRepo.Update(tx, totpDto)
+	} else {
+		err = ms.totpRepo.Create(tx, totpDto)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", "", err
+	}
+
+	return secret, mfa.URI(ms.cfg.TOTPIssuer, credentials.Email, secret), nil
+}
+
+// ConfirmTOTP включает второй фактор и выдает новый набор кодов восстановления
+func (ms *MFAService) ConfirmTOTP(ctx context.Context, credentialsId int64, code string) ([]string, error) {
+	if ms.cipher == nil {
+		return nil, utils.MFANotConfigured
+	}
+
+	tx := ms.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	totpDto := new(dto.TOTPDto)
+	if err := ms.totpRepo.GetByCredentialsIdForUpdate(tx, credentialsId, totpDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.TOTPNotEnrolled
+		}
+		return nil, err
+	}
+	if totpDto.ConfirmedAt.Valid {
+		return nil, utils.TOTPAlreadyEnabled
+	}
+
+	step, ok, err := ms.validateTOTP(totpDto, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, utils.InvalidMFACode
+	}
+
+	totpDto.ConfirmedAt = pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+	totpDto.LastUsedStep = step
+	if err := ms.totpRepo.Update(tx, totpDto); err != nil {
+		return nil, err
+	}
+
+	if err := ms.recoveryRepo.DeleteAllByCredentialsId(tx, credentialsId); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, ms.cfg.RecoveryCodesCount)
+	codeDtos := make([]dto.RecoveryCodeDto, 0, ms.cfg.RecoveryCodesCount)
+	for range ms.cfg.RecoveryCodesCount {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, recoveryCode)
+		codeDtos = append(codeDtos, dto.RecoveryCodeDto{
+			CredentialsId: credentialsId,
+			CodeHash:      hashOneTimeSecret(normalizeRecoveryCode(recoveryCode)),
+		})
+	}
+
+	if err := ms.recoveryRepo.CreateBatch(tx, codeDtos); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (ms *MFAService) validateTOTP(totpDto *dto.TOTPDto, code string) (int64, bool, error) {
+	secret, err := ms.cipher.Decrypt(totpDto.SecretEncrypted, secretAssociatedData(totpDto.CredentialsId))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return mfa.Validate(secret, strings.TrimSpace(code), time.Now(), totpDto.LastUsedStep)
+}
+
+func (ms *MFAService) IsTOTPEnabled(ctx context.Context, credentialsId int64) (bool, error) {
+	tx := ms.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	totpDto := new(dto.TOTPDto)
+	if err := ms.totpRepo.GetByCredentialsId(tx, credentialsId, totpDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return totpDto.ConfirmedAt.Valid, nil
+}
+
+// CreateChallenge выдает одноразовый токен, который вместе с кодом обменивается на пару токенов в VerifyMFA
+func (ms *MFAService) CreateChallenge(ctx context.Context, credentialsId int64, loginMethod string) (string, time.Time, error) {
+	tx := ms.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	token, err := generateOneTimeToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	challengeDto := dto.MFAChallengeDto{
+		CredentialsId: credentialsId,
+		TokenHash:     hashOneTimeSecret(token),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(ms.cfg.ChallengeLifeTime),
+		LoginMethod:   loginMethod,
+	}
+
+	if err := ms.challengesRepo.Create(tx, &challengeDto); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, challengeDto.ExpiresAt, nil
+}
+
+// GetChallengeCredentialsId возвращает id пользователя активного челленджа, не расходуя попытку
+func (ms *MFAService) GetChallengeCredentialsId(ctx context.Context, token string) (int64, error) {
+	challengeDto := new(dto.MFAChallengeDto)
+	if err := ms.challengesRepo.GetActiveByTokenHash(ms.db.WithContext(ctx), hashOneTimeSecret(token), challengeDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, utils.InvalidMFAChallenge
+		}
+		return 0, err
+	}
+
+	if time.Now().After(challengeDto.ExpiresAt) {
+		return 0, utils.InvalidMFAChallenge
+	}
+
+	return challengeDto.CredentialsId, nil
+}
+
+// VerifyChallenge принимает код TOTP или код восстановления и возвращает id пользователя
+// и способ входа, пройденный до второго фактора.
+// Неудачные попытки сохраняются, после исчерпания лимита челлендж отзывается.
+func (ms *MFAService) VerifyChallenge(ctx context.Context, token string, code string) (int64, string, error) {
+	if ms.cipher == nil {
+		return 0, "", utils.MFANotConfigured
+	}
+
+	tx := ms.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	challengeDto := new(dto.MFAChallengeDto)
+	if err := ms.challengesRepo.GetActiveByTokenHash(tx, hashOneTimeSecret(token), challengeDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", utils.InvalidMFAChallenge
+		}
+		return 0, "", err
+	}
+
+	if time.Now().After(challengeDto.ExpiresAt) {
+		return 0, "", utils.InvalidMFAChallenge
+	}
+
+	challengeDto.Attempts++
+	if challengeDto.Attempts > ms.cfg.ChallengeMaxAttempts {
+		challengeDto.Used = true
+		if err := ms.challengesRepo.Update(tx, challengeDto); err != nil {
+			return 0, "", err
+		}
+		if err := tx.Commit().Error; err != nil {
+			return 0, "", err
+		}
+		return 0, "", utils.TooManyMFAAttempts
+	}
+
+	ok, err := ms.checkCode(tx, challengeDto.CredentialsId, code)
+	if err != nil {
+		return 0, "", err
+	}
+
+	challengeDto.Used = ok
+	if err := ms.challengesRepo.Update(tx, challengeDto); err != nil {
+		return 0, "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return 0, "", err
+	}
+
+	if !ok {
+		return 0, "", utils.InvalidMFACode
+	}
+
+	return challengeDto.CredentialsId, challengeDto.LoginMethod, nil
+}
+
+func (ms *MFAService) checkCode(tx *gorm.DB, credentialsId int64, code string) (bool, error) {
+	totpDto := new(dto.TOTPDto)
+	if err := ms.totpRepo.GetByCredentialsIdForUpdate(tx, credentialsId, totpDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, utils.InvalidMFAChallenge
+		}
+		return false, err
+	}
+	if !totpDto.ConfirmedAt.Valid {
+		return false, utils.InvalidMFAChallenge
+	}
+
+	step, ok, err := ms.validateTOTP(totpDto, code)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		totpDto.LastUsedStep = step
+		if err := ms.totpRepo.Update(tx, totpDto); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	used, err := ms.recoveryRepo.UseByCodeHash(tx, credentialsId, hashOneTimeSecret(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+
+	return used == 1, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/dto"
+	cryptoRand "crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	errOneTimeCodeInvalid          = errors.New("one-time code is invalid or expired")
+	errOneTimeCodeAttemptsExceeded = errors.New("one-time code attempts exceeded")
+)
+
+// OneTimeCodeService выдает и проверяет коды из письма: подтверждения почты и входа без пароля.
+// Методы работают в транзакции вызывающего сервиса, который отвечает за поиск пользователя и отправку письма.
+type OneTimeCodeService struct {
+	codesRepo oneTimeCodesRepository
+	cfg       *config.OneTimeCodeConfig
+}
+
+func NewOneTimeCodeService(codesRepo oneTimeCodesRepository, cfg *config.OneTimeCodeConfig) *OneTimeCodeService {
+	return &OneTimeCodeService{
+		codesRepo: codesRepo,
+		cfg:       cfg,
+	}
+}
+
+// Код используется и для входа без пароля, поэтому берется из криптографического генератора
+func generateRandomCode() (string, error) {
+	letterBytes := "0123456789"
+	b := make([]byte, 6)
+	for i := range b {
+		n, err := cryptoRand.Int(cryptoRand.Reader, big.NewInt(int64(len(letterBytes))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = letterBytes[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// Issue гасит ранее выданные коды пользователя, сохраняет хэш нового и возвращает сам код для письма
+func (oc *OneTimeCodeService) Issue(tx *gorm.DB, credentialsId int64) (string, error) {
+	if err := oc.codesRepo.InvalidateAllByCredentialsId(tx, credentialsId); err != nil {
+		return "", err
+	}
+
+	code, err := generateRandomCode()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	codeDto := dto.OneTimeCodeDto{
+		CredentialsId: credentialsId,
+		CodeHash:      hashOneTimeSecret(code),
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(oc.cfg.CodeLifeTime),
+	}
+
+	if err := oc.codesRepo.Create(tx, &codeDto); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// IssueLimited выдает код, только если прошла пауза после прошлого и не исчерпан дневной лимит; иначе возвращает пустую строку.
+// Чтобы параллельные запросы не прошли проверку вместе, вызывающий сервис блокирует строку пользователя.
+func (oc *OneTimeCodeService) IssueLimited(tx *gorm.DB, credentialsId int64) (string, error) {
+	now := time.Now().UTC()
+
+	lastCode := new(dto.OneTimeCodeDto)
+	err := oc.codesRepo.GetLastByCredentialsId(tx, credentialsId, lastCode)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if err == nil && now.Before(lastCode.CreatedAt.Add(oc.cfg.ResendCooldown)) {
+		return "", nil
+	}
+
+	cnt, err := oc.codesRepo.GetCountCreatedSince(tx, credentialsId, now.Add(-24*time.Hour))
+	if err != nil {
+		return "", err
+	}
+	if cnt >= int64(oc.cfg.DailyLimit) {
+		return "", nil
+	}
+
+	return oc.Issue(tx, credentialsId)
+}
+
+// Verify гасит последний выданный код, если он совпал. Отсутствующий или истекший код дает errOneTimeCodeInvalid,
+// исчерпанные попытки - errOneTimeCodeAttemptsExceeded. Неверный код увеличивает счетчик попыток и тоже дает
+// errOneTimeCodeInvalid: вызывающий сервис должен зафиксировать транзакцию, чтобы попытка учлась.
+func (oc *OneTimeCodeService) Verify(tx *gorm.DB, credentialsId int64, code string) error {
+	codeDto := new(dto.OneTimeCodeDto)
+	if err := oc.codesRepo.GetLastActiveByCredentialsId(tx, credentialsId, codeDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errOneTimeCodeInvalid
+		}
+		return err
+	}
+
+	if time.Now().After(codeDto.ExpiresAt) {
+		return errOneTimeCodeInvalid
+	}
+
+	if codeDto.Attempts >= oc.cfg.MaxAttempts {
+		return errOneTimeCodeAttemptsExceeded
+	}
+
+	if subtle.ConstantTimeCompare([]byte(codeDto.CodeHash), []byte(hashOneTimeSecret(code))) != 1 {
+		codeDto.Attempts++
+		if err := oc.codesRepo.Update(tx, codeDto); err != nil {
+			return err
+		}
+		return errOneTimeCodeInvalid
+	}
+
+	codeDto.Used = true
+	return oc.codesRepo.Update(tx, codeDto)
+}
+
+// rejectOneTimeCode фиксирует учтенную Verify попытку и возвращает ошибку rejected вместо ошибки проверки кода
+func rejectOneTimeCode(tx *gorm.DB, err error, rejected error) error {
+	if !errors.Is(err, errOneTimeCodeInvalid) && !errors.Is(err, errOneTimeCodeAttemptsExceeded) {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return rejected
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Одноразовые секреты (коды подтверждения и входа, токены сброса пароля и MFA челленджей, коды восстановления)
+// хранятся только в виде SHA-256: у них высокая энтропия или ограниченное число попыток, поэтому соль не нужна.
+
+// generateOneTimeToken выдает 256 бит из криптографического генератора
+func generateOneTimeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := cryptoRand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashOneTimeSecret(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"AuthService/internal/dto"
+	"AuthService/internal/metrics"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Запас при инкрементальной синхронизации: revoked_at берется из NOW() транзакции,
+// поэтому долгие транзакции могут закоммитить отзыв с меткой времени из прошлого.
+const revocationSyncOverlap = time.Minute
+
+// RevocationCache хранит в памяти множество отозванных, но еще не истекших access токенов.
+// Кэш периодически догружает изменения из issued_jwt_token и не требует обращения к БД при проверке.
+type RevocationCache struct {
+	db       *gorm.DB
+	tRepo    tokensRepository
+	ttl      time.Duration
+	interval time.Duration
+
+	mu       sync.RWMutex
+	revoked  map[string]time.Time
+	cursor   time.Time
+	syncedAt time.Time
+}
+
+func NewRevocationCache(db *gorm.DB, tRepo tokensRepository, ttl time.Duration, interval time.Duration) *RevocationCache {
+	return &RevocationCache{
+		db:       db,
+		tRepo:    tRepo,
+		ttl:      ttl,
+		interval: interval,
+		revoked:  make(map[string]time.Time),
+	}
+}
+
+// Fresh сообщает, можно ли доверять кэшу: он синхронизировался хотя бы раз и не отстал больше чем на три периода.
+func (rc *RevocationCache) Fresh() bool {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	return !rc.syncedAt.IsZero() && time.Since(rc.syncedAt) < 3*rc.interval
+}
+
+func (rc *RevocationCache) IsRevoked(jti string) bool {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	_, ok := rc.revoked[jti]
+	return ok
+}
+
+func (rc *RevocationCache) Run(ctx context.Context) {
+	ticker := time.NewTicker(rc.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := rc.Sync(ctx); err != nil {
+			metrics.IncRevocationCacheSyncErrors()
+			log.Printf("Failed to sync revocation cache: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rc *RevocationCache) Sync(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	rc.mu.RLock()
+	cursor := rc.cursor
+	rc.mu.RUnlock()
+
+	since := cursor.Add(-revocationSyncOverlap)
+	if cursor.IsZero() {
+		// Токен, отозванный раньше чем ttl назад, уже истек сам
+		since = now.Add(-rc.ttl - revocationSyncOverlap)
+	}
+
+	var tokenDtos []dto.TokenDto
+	if err := rc.tRepo.GetRevokedAccessTokensSince(rc.db.WithContext(ctx), since, &tokenDtos); err != nil {
+		return err
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for _, tokenDto := range tokenDtos {
+		revokedAt := tokenDto.RevokedAt.Time
+		rc.revoked[tokenDto.JTI] = revokedAt.Add(rc.ttl)
+		if revokedAt.After(rc.cursor) {
+			rc.cursor = revokedAt
+		}
+	}
+	if rc.cursor.IsZero() {
+		rc.cursor = since
+	}
+
+	for jti, until := range rc.revoked {
+		if now.After(until.Add(revocationSyncOverlap)) {
+			delete(rc.revoked, jti)
+		}
+	}
+
+	rc.syncedAt = time.Now()
+	metrics.SetRevocationCacheSize(len(rc.revoked))
+
+	return nil
+}
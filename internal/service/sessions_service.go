@@ -0,0 +1,71 @@
+package service
+
+import (
+	"AuthService/internal/dto"
+	"AuthService/internal/entity"
+	"AuthService/internal/utils"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionsService struct {
+	db    *gorm.DB
+	sRepo sessionsRepository
+	tRepo tokensRepository
+}
+
+func NewSessionsService(db *gorm.DB, sRepo sessionsRepository, tRepo tokensRepository) *SessionsService {
+	return &SessionsService{
+		db:    db,
+		sRepo: sRepo,
+		tRepo: tRepo,
+	}
+}
+
+func (ss *SessionsService) ListSessions(ctx context.Context, subjectId int64) ([]entity.Session, error) {
+	tx := ss.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// Сессия активна, пока в ее семействе есть неотозванный refresh токен
+	familyIds, err := ss.tRepo.GetActiveFamilyIdsBySubjectId(tx, subjectId)
+	if err != nil {
+		return nil, err
+	}
+	if len(familyIds) == 0 {
+		return []entity.Session{}, nil
+	}
+
+	var sessionDtos []dto.SessionDto
+	if err := ss.sRepo.GetActiveBySubjectIdAndIds(tx, subjectId, familyIds, time.Now().UTC(), &sessionDtos); err != nil {
+		return nil, err
+	}
+
+	sessions := make([]entity.Session, 0, len(sessionDtos))
+	for _, sessionDto := range sessionDtos {
+		sessions = append(sessions, sessionDto.ToSessionEntity())
+	}
+
+	return sessions, nil
+}
+
+func (ss *SessionsService) RevokeSession(ctx context.Context, subjectId int64, sessionId string) error {
+	tx := ss.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	sessionDto := new(dto.SessionDto)
+	if err := ss.sRepo.GetBySubjectIdAndId(tx, subjectId, sessionId, sessionDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.SessionNotFound
+		}
+		return err
+	}
+
+	if err := ss.tRepo.RevokeAllTokensByFamilyId(tx, sessionDto.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
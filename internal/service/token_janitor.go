@@ -0,0 +1,142 @@
+package service
+
+import (
+	"AuthService/internal/config"
+	"AuthService/internal/metrics"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TokenJanitor периодически удаляет из хранилища истекшие токены. Отозванные и обмененные токены хранятся
+// до истечения срока: по ним обнаруживается повторное использование refresh токена.
+// Заодно удаляются истекшие сессии и счетчики попыток входа без блокировки, окно неудач которых уже прошло.
+type TokenJanitor struct {
+	db       *gorm.DB
+	tRepo    tokensRepository
+	sRepo    sessionsRepository
+	laRepo   loginAttemptsRepository
+	cfg      *config.TokenPurgeConfig
+	loginCfg *config.LoginProtectionConfig
+	// Строки без expires_at удаляются через столько после отзыва
+	refreshLifeTime time.Duration
+}
+
+func NewTokenJanitor(db *gorm.DB, tRepo tokensRepository, sRepo sessionsRepository, laRepo loginAttemptsRepository, cfg *config.TokenPurgeConfig, loginCfg *config.LoginProtectionConfig, refreshLifeTime time.Duration) *TokenJanitor {
+	return &TokenJanitor{
+		db:              db,
+		tRepo:           tRepo,
+		sRepo:           sRepo,
+		laRepo:          laRepo,
+		cfg:             cfg,
+		loginCfg:        loginCfg,
+		refreshLifeTime: refreshLifeTime,
+	}
+}
+
+func (tj *TokenJanitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(tj.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := tj.Purge(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			metrics.IncTokenPurgeErrors()
+			log.Printf("Failed to purge tokens: %v", err)
+		}
+		if deleted > 0 {
+			log.Printf("Purged %d tokens", deleted)
+		}
+
+		deleted, err = tj.PurgeSessions(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			metrics.IncTokenPurgeErrors()
+			log.Printf("Failed to purge sessions: %v", err)
+		}
+		if deleted > 0 {
+			log.Printf("Purged %d sessions", deleted)
+		}
+
+		deleted, err = tj.PurgeLoginAttempts(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("Failed to purge login attempts: %v", err)
+		}
+		if deleted > 0 {
+			log.Printf("Purged %d login attempts", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge удаляет токены пачками по BatchSize, пока не останется подходящих строк или не будет отменен контекст.
+func (tj *TokenJanitor) Purge(ctx context.Context) (int64, error) {
+	return tj.purge(ctx, func(db *gorm.DB, now time.Time) (int64, error) {
+		deleted, err := tj.tRepo.DeleteExpiredTokens(db, now, now.Add(-tj.refreshLifeTime), tj.cfg.BatchSize)
+		metrics.AddTokenPurgeDeletedRows(deleted)
+		return deleted, err
+	})
+}
+
+// PurgeSessions удаляет истекшие сессии. Срок сессии продлевается при каждом обмене refresh токена,
+// поэтому все токены такой сессии уже истекли. Они удаляются явно, а не каскадом, чтобы попасть в метрику очистки.
+func (tj *TokenJanitor) PurgeSessions(ctx context.Context) (int64, error) {
+	return tj.purge(ctx, func(db *gorm.DB, now time.Time) (int64, error) {
+		tx := db.Begin()
+		defer tx.Rollback()
+
+		ids, err := tj.sRepo.GetExpiredIdsForUpdate(tx, now, tj.cfg.BatchSize)
+		if err != nil || len(ids) == 0 {
+			return 0, err
+		}
+
+		tokens, err := tj.tRepo.DeleteTokensByFamilyIds(tx, ids)
+		if err != nil {
+			return 0, err
+		}
+
+		deleted, err := tj.sRepo.DeleteByIds(tx, ids)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := tx.Commit().Error; err != nil {
+			return 0, err
+		}
+
+		metrics.AddTokenPurgeDeletedRows(tokens)
+		return deleted, nil
+	})
+}
+
+// PurgeLoginAttempts удаляет устаревшие счетчики попыток входа
+func (tj *TokenJanitor) PurgeLoginAttempts(ctx context.Context) (int64, error) {
+	return tj.purge(ctx, func(db *gorm.DB, now time.Time) (int64, error) {
+		return tj.laRepo.DeleteStale(db, now, now.Add(-tj.loginCfg.FailureWindow), tj.cfg.BatchSize)
+	})
+}
+
+func (tj *TokenJanitor) purge(ctx context.Context, deleteBatch func(db *gorm.DB, now time.Time) (int64, error)) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		deleted, err := deleteBatch(tj.db.WithContext(ctx), time.Now().UTC())
+		if err != nil {
+			return total, err
+		}
+
+		total += deleted
+
+		if deleted < int64(tj.cfg.BatchSize) {
+			return total, nil
+		}
+	}
+
+	return total, ctx.Err()
+}
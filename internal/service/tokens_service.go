@@ -1,45 +1,70 @@
 package service
 
 import (
+	"AuthService/internal/config"
 	"AuthService/internal/dto"
+	"AuthService/internal/entity"
+	"AuthService/internal/repository"
+	"AuthService/internal/signing"
 	"AuthService/internal/utils"
 	"context"
+	"errors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"gorm.io/gorm"
-	"log"
-	"os"
-	"strconv"
+	"slices"
 	"time"
 )
 
-const secretKeyName = "JWT_SECRET_KEY"
-const refreshLifeTimeName = "JWT_REFRESH_LIFE_TIME_DAY"
-const accessLifeTimeName = "JWT_ACCESS_LIFE_TIME_MINUTE"
-
 const (
 	accessToken  = "access"
 	refreshToken = "refresh"
 )
 
 type TokensService struct {
-	db     *gorm.DB
-	crRepo credentialsRepository
-	tRepo  tokensRepository
+	db          *gorm.DB
+	crRepo      credentialsRepository
+	tRepo       tokensRepository
+	sRepo       sessionsRepository
+	keys        *signing.KeyRing
+	revocations *RevocationCache
+	cfg         *config.JWTConfig
+	parser      *jwt.Parser
 }
 
 type tokenInfo struct {
 	tokenString string
 	jti         string
+	iat         int64
 	exp         int64
 	typeToken   string
 }
 
-func NewTokensService(db *gorm.DB, crRepo credentialsRepository, tRepo tokensRepository) *TokensService {
+func (ti tokenInfo) issuedAt() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Unix(ti.iat, 0).UTC(), Valid: true}
+}
+
+func (ti tokenInfo) expiresAt() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Unix(ti.exp, 0).UTC(), Valid: true}
+}
+
+func NewTokensService(db *gorm.DB, crRepo credentialsRepository, tRepo tokensRepository, sRepo sessionsRepository, keys *signing.KeyRing, revocations *RevocationCache, cfg *config.JWTConfig) *TokensService {
 	return &TokensService{
-		db:     db,
-		crRepo: crRepo,
-		tRepo:  tRepo,
+		db:          db,
+		crRepo:      crRepo,
+		tRepo:       tRepo,
+		sRepo:       sRepo,
+		keys:        keys,
+		revocations: revocations,
+		cfg:         cfg,
+		parser: jwt.NewParser(
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithLeeway(cfg.ClockSkew),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
 	}
 }
 
@@ -51,7 +76,51 @@ func (ts *TokensService) RevokeTokenByJTI(ctx context.Context, jti string) error
 		return err
 	}
 
-	return nil
+	return tx.Commit().Error
+}
+
+// RevokeRefreshTokenByJTI отзывает refresh токен вместе с парным access токеном.
+// Неотозванной в семействе остается только последняя выданная пара, поэтому отзывается все семейство.
+func (ts *TokensService) RevokeRefreshTokenByJTI(ctx context.Context, jti string) error {
+	tx := ts.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	tokenDto := new(dto.TokenDto)
+	if err := ts.tRepo.GetTokenByJTIForUpdate(tx, jti, tokenDto); err != nil {
+		return err
+	}
+
+	if err := ts.tRepo.RevokeAllTokensByFamilyId(tx, tokenDto.FamilyId); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// RevokeSessionByPair отзывает все токены сессии, к которой принадлежит пара access/refresh.
+func (ts *TokensService) RevokeSessionByPair(ctx context.Context, accessJti string, refreshJti string) error {
+	tx := ts.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	accessDto := new(dto.TokenDto)
+	if err := ts.tRepo.GetTokenByJTIForUpdate(tx, accessJti, accessDto); err != nil {
+		return err
+	}
+
+	refreshDto := new(dto.TokenDto)
+	if err := ts.tRepo.GetTokenByJTIForUpdate(tx, refreshJti, refreshDto); err != nil {
+		return err
+	}
+
+	if accessDto.FamilyId != refreshDto.FamilyId || accessDto.SubjectId != refreshDto.SubjectId {
+		return utils.InvalidToken
+	}
+
+	if err := ts.tRepo.RevokeAllTokensByFamilyId(tx, accessDto.FamilyId); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
 }
 
 func (ts *TokensService) RevokeAllTokensWithBySubjectId(ctx context.Context, subjectId int64) error {
@@ -78,84 +147,307 @@ func (ts *TokensService) GetTokenByJTI(ctx context.Context, jti string) (dto.Tok
 }
 
 func (ts *TokensService) VerifyToken(ctx context.Context, tokenString string, expectedType string) (string, error) {
+	claims, err := ts.VerifyTokenClaims(ctx, tokenString, expectedType)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.JTI, nil
+}
+
+// VerifyTokenClaims проверяет подпись и claims токена, а затем его состояние в хранилище.
+func (ts *TokensService) VerifyTokenClaims(ctx context.Context, tokenString string, expectedType string) (entity.TokenClaims, error) {
 	tx := ts.db.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	secretKey := os.Getenv(secretKeyName)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
+	claims, err := ts.ParseTokenClaims(tokenString, expectedType)
+	if err != nil {
+		return entity.TokenClaims{}, err
+	}
+
+	tokenDto := new(dto.TokenDto)
+	if err := ts.tRepo.GetTokenByJTI(tx, claims.JTI, tokenDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.TokenClaims{}, utils.InvalidToken
+		}
+		return entity.TokenClaims{}, err
+	}
+
+	if tokenDto.Revoked {
+		return entity.TokenClaims{}, utils.RevokedToken
+	}
+
+	return claims, nil
+}
+
+func (ts *TokensService) ParseToken(tokenString string, expectedType string) (string, error) {
+	claims, err := ts.ParseTokenClaims(tokenString, expectedType)
 	if err != nil {
 		return "", err
 	}
 
-	if !token.Valid {
-		return "", nil
+	return claims.JTI, nil
+}
+
+func (ts *TokensService) ParseTokenClaims(tokenString string, expectedType string) (entity.TokenClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := ts.parser.ParseWithClaims(tokenString, claims, ts.keyFunc); err != nil {
+		return entity.TokenClaims{}, mapJWTError(err)
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		typeToken, ok := claims["type"].(string)
-		if !ok {
-			return "", utils.InvalidToken
+	typeToken, ok := claims["type"].(string)
+	if !ok {
+		return entity.TokenClaims{}, utils.InvalidTokenClaims
+	}
+
+	if typeToken != expectedType {
+		return entity.TokenClaims{}, utils.WrongTokenType
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return entity.TokenClaims{}, utils.InvalidTokenClaims
+	}
+
+	id, ok := claims["id"].(float64)
+	if !ok {
+		return entity.TokenClaims{}, utils.InvalidTokenClaims
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return entity.TokenClaims{}, utils.InvalidTokenClaims
+	}
+
+	res := entity.TokenClaims{
+		JTI:       jti,
+		SubjectId: int64(id),
+		TokenType: typeToken,
+		ExpiresAt: exp.Time,
+	}
+	res.Email, _ = claims["email"].(string)
+	res.SessionId, _ = claims["sid"].(string)
+	res.EmailVerified, _ = claims["email_verified"].(bool)
+	if roles, ok := claims["roles"].([]interface{}); ok {
+		for _, role := range roles {
+			if role, ok := role.(string); ok {
+				res.Roles = append(res.Roles, role)
+			}
+		}
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		res.IssuedAt = iat.Time
+	}
+
+	return res, nil
+}
+
+// VerifyAccessTokenClaims проверяет access токен без обращения к БД, если кэш отзывов актуален.
+// Пока кэш не синхронизирован, отзыв проверяется по issued_jwt_token.
+func (ts *TokensService) VerifyAccessTokenClaims(ctx context.Context, tokenString string) (entity.TokenClaims, error) {
+	claims, err := ts.ParseTokenClaims(tokenString, accessToken)
+	if err != nil {
+		return entity.TokenClaims{}, err
+	}
+
+	if ts.revocations.Fresh() {
+		if ts.revocations.IsRevoked(claims.JTI) {
+			return entity.TokenClaims{}, utils.RevokedToken
+		}
+
+		return claims, nil
+	}
+
+	tokenDto, err := ts.GetTokenByJTI(ctx, claims.JTI)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.TokenClaims{}, utils.InvalidToken
+		}
+		return entity.TokenClaims{}, err
+	}
+
+	if tokenDto.Revoked {
+		return entity.TokenClaims{}, utils.RevokedToken
+	}
+
+	return claims, nil
+}
+
+// VerifyAccessTokensClaims проверяет пачку access токенов; при неактуальном кэше отзывов все токены
+// проверяются в хранилище одним запросом. Результаты и ошибки возвращаются в порядке входных токенов.
+func (ts *TokensService) VerifyAccessTokensClaims(ctx context.Context, tokenStrings []string) ([]entity.TokenClaims, []error, error) {
+	claims := make([]entity.TokenClaims, len(tokenStrings))
+	errs := make([]error, len(tokenStrings))
+
+	jtis := make([]string, 0, len(tokenStrings))
+	for i, tokenString := range tokenStrings {
+		claims[i], errs[i] = ts.ParseTokenClaims(tokenString, accessToken)
+		if errs[i] == nil {
+			jtis = append(jtis, claims[i].JTI)
 		}
+	}
+
+	if len(jtis) == 0 {
+		return claims, errs, nil
+	}
 
-		if typeToken != expectedType {
-			return "", utils.InvalidToken
+	revoked := make(map[string]bool, len(jtis))
+	if ts.revocations.Fresh() {
+		for _, jti := range jtis {
+			revoked[jti] = ts.revocations.IsRevoked(jti)
 		}
+	} else {
+		tx := ts.db.WithContext(ctx).Begin()
+		defer tx.Rollback()
 
-		jti, ok := claims["jti"].(string)
-		if !ok {
-			return "", utils.InvalidToken
+		var tokenDtos []dto.TokenDto
+		if err := ts.tRepo.GetTokensByJTIs(tx, jtis, &tokenDtos); err != nil {
+			return nil, nil, err
 		}
 
-		tokenDto := new(dto.TokenDto)
-		if err := ts.tRepo.GetTokenByJTI(tx, jti, tokenDto); err != nil {
-			return "", err
+		for _, tokenDto := range tokenDtos {
+			revoked[tokenDto.JTI] = tokenDto.Revoked
 		}
+	}
 
-		if tokenDto.Revoked {
-			return "", utils.RevokedToken
+	for i := range claims {
+		if errs[i] != nil {
+			continue
 		}
 
-		return jti, nil
+		isRevoked, ok := revoked[claims[i].JTI]
+		switch {
+		case !ok:
+			errs[i] = utils.InvalidToken
+		case isRevoked:
+			errs[i] = utils.RevokedToken
+		}
+		if errs[i] != nil {
+			claims[i] = entity.TokenClaims{}
+		}
 	}
 
-	return "", utils.InvalidToken
+	return claims, errs, nil
 }
 
-func (ts *TokensService) CreateAccessRefreshPairTokens(ctx context.Context, credentialsId int64, email string) (string, string, error) {
+func (ts *TokensService) keyFunc(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if !slices.Contains(ts.cfg.AllowedAlgorithms, alg) {
+		return nil, utils.TokenAlgorithmNotAllowed
+	}
+
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, utils.InvalidToken
+	}
+
+	key, ok := ts.keys.Lookup(kid)
+	if !ok {
+		return nil, utils.BadTokenSignature
+	}
+
+	// Алгоритм из заголовка должен совпадать с алгоритмом ключа
+	if key.Method.Alg() != alg {
+		return nil, utils.TokenAlgorithmNotAllowed
+	}
+
+	return key.Public, nil
+}
+
+func mapJWTError(err error) error {
+	switch {
+	case errors.Is(err, utils.TokenAlgorithmNotAllowed):
+		return utils.TokenAlgorithmNotAllowed
+	case errors.Is(err, utils.BadTokenSignature), errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return utils.BadTokenSignature
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return utils.ExpiredToken
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer),
+		errors.Is(err, jwt.ErrTokenInvalidAudience),
+		errors.Is(err, jwt.ErrTokenRequiredClaimMissing),
+		errors.Is(err, jwt.ErrTokenNotValidYet),
+		errors.Is(err, jwt.ErrTokenUsedBeforeIssued),
+		errors.Is(err, jwt.ErrTokenInvalidClaims):
+		return utils.InvalidTokenClaims
+	default:
+		return utils.InvalidToken
+	}
+}
+
+func (ts *TokensService) CreateAccessRefreshPairTokens(ctx context.Context, credentials entity.Credentials, client entity.ClientInfo, loginMethod string) (string, string, error) {
 	tx := ts.db.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	refreshToken, err := ts.createJWTToken(credentialsId, email, "refresh")
+	sessionId := uuid.New().String()
+
+	accessToken, refreshToken, err := ts.createPairTokens(credentials, sessionId)
 	if err != nil {
 		return "", "", err
 	}
 
-	accessToken, err := ts.createJWTToken(credentialsId, email, "access")
-	if err != nil {
+	now := time.Now().UTC()
+	sessionDto := dto.SessionDto{
+		ID:          sessionId,
+		SubjectId:   credentials.ID,
+		UserAgent:   client.UserAgent,
+		IP:          client.IP,
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		ExpiresAt:   time.Unix(refreshToken.exp, 0).UTC(),
+		LoginMethod: loginMethod,
+	}
+
+	if err := ts.sRepo.Create(tx, &sessionDto); err != nil {
 		return "", "", err
 	}
 
-	refreshTokenEntity := dto.TokenDto{
-		JTI:       refreshToken.jti,
-		SubjectId: credentialsId,
-		TokenType: refreshToken.typeToken,
-		Revoked:   false,
+	if err := ts.savePairTokens(tx, credentials.ID, sessionId, accessToken, refreshToken); err != nil {
+		return "", "", err
 	}
 
-	accessTokenEntity := dto.TokenDto{
-		JTI:       accessToken.jti,
-		SubjectId: credentialsId,
-		TokenType: accessToken.typeToken,
-		Revoked:   false,
+	if err := tx.Commit().Error; err != nil {
+		return "", "", err
 	}
 
-	if err := ts.tRepo.Create(tx, &accessTokenEntity); err != nil {
+	return accessToken.tokenString, refreshToken.tokenString, nil
+}
+
+// UpdatePasswordKeepingSession меняет пароль, отзывает остальные сессии пользователя и выдает новую пару токенов
+// в сессии familyId. Все в одной транзакции: иначе при ошибке выдачи пароль был бы уже сменен,
+// а клиент остался бы со старыми токенами без новой пары.
+func (ts *TokensService) UpdatePasswordKeepingSession(ctx context.Context, credentials entity.Credentials, passwordHash string, familyId string, client entity.ClientInfo) (string, string, error) {
+	tx := ts.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := ts.crRepo.GetById(tx, credentialsDto, credentials.ID); err != nil {
+		return "", "", err
+	}
+
+	credentialsDto.Password = passwordHash
+	if err := ts.crRepo.Update(tx, credentialsDto); err != nil {
+		return "", "", err
+	}
+
+	if err := ts.tRepo.RevokeAllTokensBySubjectIdExceptFamilyId(tx, credentials.ID, familyId); err != nil {
+		return "", "", err
+	}
+
+	accessToken, refreshToken, err := ts.createPairTokens(credentials, familyId)
+	if err != nil {
+		return "", "", err
+	}
+
+	expiresAt := time.Unix(refreshToken.exp, 0).UTC()
+	if err := ts.sRepo.Touch(tx, familyId, client.UserAgent, client.IP, time.Now().UTC(), expiresAt); err != nil {
+		return "", "", err
+	}
+
+	if err := ts.tRepo.RevokeAllTokensByFamilyId(tx, familyId); err != nil {
 		return "", "", err
 	}
 
-	if err := ts.tRepo.Create(tx, &refreshTokenEntity); err != nil {
+	if err := ts.savePairTokens(tx, credentials.ID, familyId, accessToken, refreshToken); err != nil {
 		return "", "", err
 	}
 
@@ -166,69 +458,173 @@ func (ts *TokensService) CreateAccessRefreshPairTokens(ctx context.Context, cred
 	return accessToken.tokenString, refreshToken.tokenString, nil
 }
 
-func (ts *TokensService) createJWTToken(credentialsId int64, email string, typeToken string) (tokenInfo, error) {
-	secretKey := os.Getenv(secretKeyName)
-	if len(secretKey) == 0 {
-		log.Fatalf("%s is empty", secretKeyName)
-		return tokenInfo{}, utils.InternalServerError
+// RotateRefreshToken обменивает refresh токен на новую пару в том же семействе (сессии).
+// Повторное предъявление уже обмененного токена отзывает все семейство и возвращает utils.RefreshTokenReused.
+func (ts *TokensService) RotateRefreshToken(ctx context.Context, jti string, client entity.ClientInfo) (dto.TokenDto, string, string, error) {
+	tx := ts.db.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	tokenDto := new(dto.TokenDto)
+	if err := ts.tRepo.GetTokenByJTIForUpdate(tx, jti, tokenDto); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.TokenDto{}, "", "", utils.InvalidToken
+		}
+		return dto.TokenDto{}, "", "", err
 	}
 
-	var (
-		lifeTime int64
-		err      error
-		exp      int64
-	)
+	if tokenDto.Rotated {
+		return *tokenDto, "", "", ts.revokeReusedFamily(tx, tokenDto.FamilyId)
+	}
 
-	if typeToken == "refresh" {
-		lifeTime, err = strconv.ParseInt(os.Getenv(refreshLifeTimeName), 0, 64)
-		if err != nil || lifeTime < 1 {
-			log.Fatalf("Invalid %s", refreshLifeTimeName)
-			return tokenInfo{}, utils.InternalServerError
-		}
+	if tokenDto.Revoked {
+		return *tokenDto, "", "", utils.RevokedToken
+	}
+
+	credentialsDto := new(dto.CredentialsDto)
+	if err := ts.crRepo.GetById(tx, credentialsDto, tokenDto.SubjectId); err != nil {
+		return *tokenDto, "", "", err
+	}
+
+	accessToken, refreshToken, err := ts.createPairTokens(credentialsDto.ToCredentialsEntity(), tokenDto.FamilyId)
+	if err != nil {
+		return *tokenDto, "", "", err
+	}
+
+	// Сессия обновляется до записей в хранилище токенов: Redis не участвует в SQL транзакции,
+	// поэтому записи в него идут последними, перед самым коммитом
+	expiresAt := time.Unix(refreshToken.exp, 0).UTC()
+	if err := ts.sRepo.Touch(tx, tokenDto.FamilyId, client.UserAgent, client.IP, time.Now().UTC(), expiresAt); err != nil {
+		return *tokenDto, "", "", err
+	}
 
-		exp = time.Now().Add(time.Hour * 24 * time.Duration(lifeTime)).Unix()
-	} else if typeToken == "access" {
-		lifeTime, err = strconv.ParseInt(os.Getenv(accessLifeTimeName), 0, 64)
-		if err != nil || lifeTime < 1 {
-			log.Fatalf("Invalid %s", accessLifeTimeName)
-			return tokenInfo{}, utils.InternalServerError
+	// Отметка об обмене ставится до отзыва семейства: в хранилище без блокировок строк
+	// проигравший гонку запрос получает ErrTokenAlreadyRotated, ничего не успев изменить
+	if err := ts.tRepo.MarkRotatedByJTI(tx, tokenDto.JTI); err != nil {
+		if errors.Is(err, repository.ErrTokenAlreadyRotated) {
+			return *tokenDto, "", "", ts.revokeReusedFamily(tx, tokenDto.FamilyId)
 		}
+		return *tokenDto, "", "", err
+	}
 
-		exp = time.Now().Add(time.Minute * time.Duration(lifeTime)).Unix()
-	} else {
+	if err := ts.tRepo.RevokeAllTokensByFamilyId(tx, tokenDto.FamilyId); err != nil {
+		return *tokenDto, "", "", err
+	}
+
+	if err := ts.savePairTokens(tx, credentialsDto.ID, tokenDto.FamilyId, accessToken, refreshToken); err != nil {
+		return *tokenDto, "", "", err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return *tokenDto, "", "", err
+	}
+
+	return *tokenDto, accessToken.tokenString, refreshToken.tokenString, nil
+}
+
+// revokeReusedFamily отзывает сессию, refresh токен которой предъявлен повторно
+func (ts *TokensService) revokeReusedFamily(tx *gorm.DB, familyId string) error {
+	if err := ts.tRepo.RevokeAllTokensByFamilyId(tx, familyId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return utils.RefreshTokenReused
+}
+
+func (ts *TokensService) createPairTokens(credentials entity.Credentials, sessionId string) (tokenInfo, tokenInfo, error) {
+	refreshToken, err := ts.createJWTToken(credentials, sessionId, "refresh")
+	if err != nil {
+		return tokenInfo{}, tokenInfo{}, err
+	}
+
+	accessToken, err := ts.createJWTToken(credentials, sessionId, "access")
+	if err != nil {
+		return tokenInfo{}, tokenInfo{}, err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+func (ts *TokensService) savePairTokens(tx *gorm.DB, credentialsId int64, familyId string, accessToken tokenInfo, refreshToken tokenInfo) error {
+	refreshTokenEntity := dto.TokenDto{
+		JTI:       refreshToken.jti,
+		SubjectId: credentialsId,
+		TokenType: refreshToken.typeToken,
+		Revoked:   false,
+		IssuedAt:  refreshToken.issuedAt(),
+		ExpiresAt: refreshToken.expiresAt(),
+		FamilyId:  familyId,
+	}
+
+	accessTokenEntity := dto.TokenDto{
+		JTI:       accessToken.jti,
+		SubjectId: credentialsId,
+		TokenType: accessToken.typeToken,
+		Revoked:   false,
+		IssuedAt:  accessToken.issuedAt(),
+		ExpiresAt: accessToken.expiresAt(),
+		FamilyId:  familyId,
+	}
+
+	if err := ts.tRepo.Create(tx, &accessTokenEntity); err != nil {
+		return err
+	}
+
+	return ts.tRepo.Create(tx, &refreshTokenEntity)
+}
+
+func (ts *TokensService) createJWTToken(credentials entity.Credentials, sessionId string, typeToken string) (tokenInfo, error) {
+	var lifeTime time.Duration
+	switch typeToken {
+	case refreshToken:
+		lifeTime = ts.cfg.RefreshLifeTime
+	case accessToken:
+		lifeTime = ts.cfg.AccessLifeTime
+	default:
 		return tokenInfo{}, utils.InternalServerError
 	}
 
 	jti := uuid.New().String()
+	key := ts.keys.Active()
+	now := time.Now()
+	exp := now.Add(lifeTime).Unix()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
+	token := jwt.NewWithClaims(key.Method,
 		jwt.MapClaims{
-			"id":    credentialsId,
-			"email": email,
-			"exp":   exp,
-			"jti":   jti,
-			"type":  typeToken,
+			"id":             credentials.ID,
+			"email":          credentials.Email,
+			"email_verified": credentials.EmailVerified,
+			"roles":          credentials.Roles,
+			"exp":            exp,
+			"iat":            now.Unix(),
+			"iss":            ts.cfg.Issuer,
+			"aud":            ts.cfg.Audience,
+			"jti":            jti,
+			"sid":            sessionId,
+			"type":           typeToken,
 		},
 	)
+	token.Header["kid"] = key.ID
 
-	tokenString, err := ts.getTokenString(token)
+	tokenString, err := ts.getTokenString(token, key)
+	if err != nil {
+		return tokenInfo{}, err
+	}
 
 	return tokenInfo{
 		tokenString: tokenString,
 		jti:         jti,
+		iat:         now.Unix(),
 		exp:         exp,
 		typeToken:   typeToken,
 	}, nil
 }
 
-func (ts *TokensService) getTokenString(token *jwt.Token) (string, error) {
-	secretKey := os.Getenv(secretKeyName)
-	if len(secretKey) == 0 {
-		log.Fatalf("%s is empty", secretKeyName)
-		return "", utils.InternalServerError
-	}
-
-	tokenString, err := token.SignedString([]byte(secretKey))
+func (ts *TokensService) getTokenString(token *jwt.Token, key *signing.Key) (string, error) {
+	tokenString, err := token.SignedString(key.Private)
 	if err != nil {
 		return "", utils.InternalServerError
 	}
@@ -0,0 +1,155 @@
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	keyFileExt     = ".pem"
+	activeFileName = "active"
+)
+
+// KeyRing хранит ключи подписи из каталога: каждый <kid>.pem - отдельный ключ,
+// файл active содержит kid ключа, которым подписываются новые токены.
+// Остальные ключи используются только для проверки, пока их файлы не удалены.
+// Алгоритм активного ключа должен входить в allowedAlgorithms, иначе подписанные им токены не пройдут проверку.
+type KeyRing struct {
+	dir               string
+	allowedAlgorithms []string
+
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active *Key
+}
+
+func NewKeyRing(dir string, allowedAlgorithms []string) (*KeyRing, error) {
+	ring := &KeyRing{dir: dir, allowedAlgorithms: allowedAlgorithms}
+	if _, err := ring.Reload(); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+func (r *KeyRing) Active() *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.active
+}
+
+func (r *KeyRing) Lookup(kid string) (*Key, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[kid]
+	return key, ok
+}
+
+func (r *KeyRing) Keys() []*Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*Key, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *Key) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return keys
+}
+
+// Reload перечитывает каталог ключей. При ошибке текущий набор ключей сохраняется.
+func (r *KeyRing) Reload() (bool, error) {
+	keys, active, err := r.load()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := r.active == nil || r.active.ID != active.ID || !sameKeys(r.keys, keys)
+	r.keys = keys
+	r.active = active
+
+	return changed, nil
+}
+
+func (r *KeyRing) load() (map[string]*Key, *Key, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := make(map[string]*Key)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != keyFileExt {
+			continue
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), keyFileExt)
+		key, err := LoadKey(filepath.Join(r.dir, entry.Name()), kid)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load key %s: %w", entry.Name(), err)
+		}
+
+		keys[kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("no %s keys found in %s", keyFileExt, r.dir)
+	}
+
+	activeKid, err := r.readActiveKid(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	active, ok := keys[activeKid]
+	if !ok {
+		return nil, nil, fmt.Errorf("active key %q not found in %s", activeKid, r.dir)
+	}
+
+	if alg := active.Method.Alg(); !slices.Contains(r.allowedAlgorithms, alg) {
+		return nil, nil, fmt.Errorf("active key %q uses algorithm %s, which is not in JWT_ALLOWED_ALGORITHMS %v", activeKid, alg, r.allowedAlgorithms)
+	}
+
+	return keys, active, nil
+}
+
+func (r *KeyRing) readActiveKid(keys map[string]*Key) (string, error) {
+	data, err := os.ReadFile(filepath.Join(r.dir, activeFileName))
+	if errors.Is(err, os.ErrNotExist) && len(keys) == 1 {
+		for kid := range keys {
+			return kid, nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("read active key id: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func sameKeys(a, b map[string]*Key) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for kid := range a {
+		if _, ok := b[kid]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
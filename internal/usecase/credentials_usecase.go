@@ -1,69 +1,120 @@
 package usecase
 
 import (
+	"AuthService/internal/config"
+	"AuthService/internal/convertor"
+	"AuthService/internal/dto"
 	"AuthService/internal/entity"
+	"AuthService/internal/metrics"
 	"AuthService/internal/utils"
+	"AuthService/internal/validation"
 	proto "AuthService/pkg/api/v1"
 	"context"
+	"errors"
 	"log"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const maxVerifyAccessTokensBatch = 100
+
 type CredentialsUseCase struct {
 	crs credentialsService
 	ts  tokensService
+	ss  sessionsService
+	lt  loginThrottleService
+	mfa mfaService
+	lc  loginCodeService
+	val *validation.Validator
+	ae  *config.AntiEnumerationConfig
 }
 
-func NewCredentialsUseCase(crs credentialsService, ts tokensService) *CredentialsUseCase {
+func NewCredentialsUseCase(crs credentialsService, ts tokensService, ss sessionsService, lt loginThrottleService, mfa mfaService, lc loginCodeService, val *validation.Validator, ae *config.AntiEnumerationConfig) *CredentialsUseCase {
 	return &CredentialsUseCase{
 		crs: crs,
 		ts:  ts,
+		ss:  ss,
+		lt:  lt,
+		mfa: mfa,
+		lc:  lc,
+		val: val,
+		ae:  ae,
 	}
 }
 
+// validateCredentials возвращает нормализованный email; новый пароль проверяется по политике паролей
+func (c CredentialsUseCase) validateCredentials(credentials *proto.Credentials, newPassword bool) (string, error) {
+	v := new(validation.Violations)
+	if credentials == nil {
+		v.Add("credentials", "Credentials are required")
+		return "", v.Err()
+	}
+
+	email := c.val.Email(v, "credentials.email", credentials.Email)
+	if newPassword {
+		c.val.NewPassword(v, "credentials.password", credentials.Password)
+	} else {
+		c.val.Password(v, "credentials.password", credentials.Password)
+	}
+
+	return email, v.Err()
+}
+
+func (c CredentialsUseCase) validateEmail(email string) (string, error) {
+	v := new(validation.Violations)
+	email = c.val.Email(v, "email", email)
+	return email, v.Err()
+}
+
 func (c CredentialsUseCase) Logout(ctx context.Context, req *proto.LogoutRequest) (*emptypb.Empty, error) {
-	jti, err := c.ts.VerifyToken(ctx, req.Tokens.Access, "access")
+	if req.Tokens == nil {
+		return nil, utils.InvalidToken
+	}
+
+	accessJti, err := c.ts.VerifyToken(ctx, req.Tokens.Access, "access")
 	if err != nil {
 		return nil, err
 	}
-	jti, err = c.ts.VerifyToken(ctx, req.Tokens.Refresh, "refresh")
+	refreshJti, err := c.ts.VerifyToken(ctx, req.Tokens.Refresh, "refresh")
 	if err != nil {
 		return nil, err
 	}
 
-	if err := c.ts.RevokeTokenByJTI(ctx, jti); err != nil {
+	if err := c.ts.RevokeSessionByPair(ctx, accessJti, refreshJti); err != nil {
 		return nil, err
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
-func (c CredentialsUseCase) RefreshTokens(ctx context.Context, req *proto.RefreshTokensRequest) (*proto.RefreshTokensResponse, error) {
-	jti, err := c.ts.VerifyToken(ctx, req.RefreshToken, "refresh")
+func (c CredentialsUseCase) LogoutAll(ctx context.Context, req *proto.LogoutAllRequest) (*emptypb.Empty, error) {
+	token, err := c.authenticate(ctx, req.Access)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := c.ts.GetTokenByJTI(ctx, jti)
-	if err != nil {
+	if err := c.ts.RevokeAllTokensWithBySubjectId(ctx, token.SubjectId); err != nil {
 		return nil, err
 	}
 
-	if token.Revoked {
-		return nil, utils.RevokedToken
-	}
+	return &emptypb.Empty{}, nil
+}
 
-	if err := c.ts.RevokeAllTokensWithBySubjectId(ctx, token.SubjectId); err != nil {
+func (c CredentialsUseCase) RefreshTokens(ctx context.Context, req *proto.RefreshTokensRequest) (*proto.RefreshTokensResponse, error) {
+	jti, err := c.ts.ParseToken(req.RefreshToken, "refresh")
+	if err != nil {
 		return nil, err
 	}
 
-	credentials, err := c.crs.GetCredentialsById(ctx, token.SubjectId)
-	if err != nil {
+	token, accessToken, refreshToken, err := c.ts.RotateRefreshToken(ctx, jti, utils.ClientInfoFromContext(ctx))
+	if errors.Is(err, utils.RefreshTokenReused) {
+		c.reportRefreshTokenReuse(ctx, token)
 		return nil, err
 	}
-
-	accessToken, refreshToken, err := c.ts.CreateAccessRefreshPairTokens(ctx, credentials.ID, credentials.Email)
 	if err != nil {
 		return nil, err
 	}
@@ -76,49 +127,145 @@ func (c CredentialsUseCase) RefreshTokens(ctx context.Context, req *proto.Refres
 	}, nil
 }
 
+func (c CredentialsUseCase) reportRefreshTokenReuse(ctx context.Context, token dto.TokenDto) {
+	metrics.IncSecurityEvent(metrics.SecurityEventRefreshTokenReuse)
+	log.Printf("Security event: refresh token %s reused, family %s of subject %d revoked", token.JTI, token.FamilyId, token.SubjectId)
+
+	credentials, err := c.crs.GetCredentialsById(ctx, token.SubjectId)
+	if err != nil {
+		log.Printf("Failed get credentials for security alert: %s", err)
+		return
+	}
+
+	body := "Повторно использован уже обновленный токен входа. Сессия на этом устройстве завершена.\nЕсли это были не вы, смените пароль."
+	if err := c.crs.SendSecurityAlertMailToEmail(credentials.Email, body); err != nil {
+		log.Printf("Failed send email: %s", err)
+	}
+}
+
 func (c CredentialsUseCase) VerifyAccessToken(ctx context.Context, req *proto.VerifyAccessTokenRequest) (*proto.VerifyAccessTokenResponse, error) {
-	jti, err := c.ts.VerifyToken(ctx, req.Access, "access")
+	claims, err := c.ts.VerifyAccessTokenClaims(ctx, req.Access)
 	if err != nil {
 		return nil, err
 	}
 
-	tokenDto, err := c.ts.GetTokenByJTI(ctx, jti)
+	return convertor.TokenClaimsToProto(claims), nil
+}
+
+func (c CredentialsUseCase) VerifyAccessTokens(ctx context.Context, req *proto.VerifyAccessTokensRequest) (*proto.VerifyAccessTokensResponse, error) {
+	if len(req.Access) > maxVerifyAccessTokensBatch {
+		return nil, utils.TooManyTokens
+	}
+
+	claims, errs, err := c.ts.VerifyAccessTokensClaims(ctx, req.Access)
 	if err != nil {
 		return nil, err
 	}
 
-	if tokenDto.TokenType != "access" {
-		return nil, utils.InvalidToken
-	}
+	results := make([]*proto.VerifyAccessTokenResult, 0, len(req.Access))
+	for i := range req.Access {
+		if errs[i] != nil {
+			results = append(results, verifyErrorToResult(errs[i]))
+			continue
+		}
 
-	if tokenDto.Revoked {
-		return nil, utils.InvalidToken
+		results = append(results, &proto.VerifyAccessTokenResult{
+			Claims: convertor.TokenClaimsToProto(claims[i]),
+		})
 	}
 
-	return &proto.VerifyAccessTokenResponse{
-		UserId: tokenDto.SubjectId,
+	return &proto.VerifyAccessTokensResponse{
+		Results: results,
 	}, nil
 }
 
+func verifyErrorToResult(err error) *proto.VerifyAccessTokenResult {
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.Convert(utils.InternalServerError)
+	}
+
+	res := &proto.VerifyAccessTokenResult{
+		Code:    int32(st.Code()),
+		Message: st.Message(),
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			res.Reason = info.Reason
+		}
+	}
+
+	return res
+}
+
 func (c CredentialsUseCase) SignIn(ctx context.Context, req *proto.SignInRequest) (*proto.SignInResponse, error) {
-	res, err := c.crs.CheckAlreadyExistsEmail(ctx, req.Credentials.Email)
+	email, err := c.validateCredentials(req.Credentials, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Попытка учитывается до проверки пароля и снимается только после успешного входа
+	ip := utils.ClientInfoFromContext(ctx).IP
+	retryAfter, accountLocked, lockedUntil, err := c.lt.Acquire(ctx, email, ip)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		metrics.IncLoginRejected()
+		return nil, utils.LoginLocked(retryAfter)
+	}
+
+	res, err := c.crs.CheckAlreadyExistsEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if !res {
-		return nil, utils.InvalidCredentials
+		c.crs.ValidateDummyPassword(req.Credentials.Password)
+		return nil, c.loginFailure(email, ip, false, lockedUntil)
 	}
 
-	credentials, err := c.crs.GetCredentialsByEmail(ctx, req.Credentials.Email)
+	credentials, err := c.crs.GetCredentialsByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 
 	if !c.crs.ValidatePassword(req.Credentials.Password, credentials.Password) {
-		return nil, utils.InvalidCredentials
+		return nil, c.loginFailure(email, ip, accountLocked, lockedUntil)
+	}
+
+	// При включенном втором факторе попытка остается учтенной до успешного VerifyMFA
+	mfaEnabled, err := c.mfa.IsTOTPEnabled(ctx, credentials.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfaEnabled {
+		if err := c.lt.Succeed(ctx, email, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	if !credentials.EmailVerified {
+		return nil, utils.EmailNotVerified
+	}
+
+	return c.completeSignIn(ctx, credentials, entity.LoginMethodPassword, mfaEnabled)
+}
+
+// completeSignIn выдает пару токенов после первого фактора, а при включенном TOTP - MFA челлендж
+func (c CredentialsUseCase) completeSignIn(ctx context.Context, credentials entity.Credentials, loginMethod string, mfaEnabled bool) (*proto.SignInResponse, error) {
+	if mfaEnabled {
+		challenge, expiresAt, err := c.mfa.CreateChallenge(ctx, credentials.ID, loginMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.SignInResponse{
+			MfaChallenge:          challenge,
+			MfaChallengeExpiresAt: timestamppb.New(expiresAt),
+		}, nil
 	}
 
-	accessToken, refreshToken, err := c.ts.CreateAccessRefreshPairTokens(ctx, credentials.ID, credentials.Email)
+	accessToken, refreshToken, err := c.ts.CreateAccessRefreshPairTokens(ctx, credentials, utils.ClientInfoFromContext(ctx), loginMethod)
 	if err != nil {
 		return nil, err
 	}
@@ -131,22 +278,201 @@ func (c CredentialsUseCase) SignIn(ctx context.Context, req *proto.SignInRequest
 	}, nil
 }
 
-func (c CredentialsUseCase) SignUp(ctx context.Context, req *proto.SignUpRequest) error {
-	res, err := c.crs.CheckAlreadyExistsEmail(ctx, req.Credentials.Email)
+func (c CredentialsUseCase) VerifyMFA(ctx context.Context, req *proto.VerifyMFARequest) (*proto.VerifyMFAResponse, error) {
+	if len(req.MfaChallenge) == 0 {
+		return nil, utils.InvalidMFAChallenge
+	}
+
+	credentialsId, err := c.mfa.GetChallengeCredentialsId(ctx, req.MfaChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := c.crs.GetCredentialsById(ctx, credentialsId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Неудачи второго фактора учитываются в счетчике почты. IP учтен при проверке пароля,
+	// поэтому после успешного входа по паролю с него снимается та попытка.
+	retryAfter, accountLocked, lockedUntil, err := c.lt.Acquire(ctx, credentials.Email, "")
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		metrics.IncLoginRejected()
+		return nil, utils.LoginLocked(retryAfter)
+	}
+
+	_, loginMethod, err := c.mfa.VerifyChallenge(ctx, req.MfaChallenge, req.Code)
+	if err != nil {
+		if errors.Is(err, utils.InvalidMFACode) || errors.Is(err, utils.TooManyMFAAttempts) {
+			metrics.IncLoginFailure(metrics.LoginScopeAccount)
+			c.reportLoginLocked(credentials.Email, accountLocked, lockedUntil)
+		}
+		return nil, err
+	}
+
+	var ip string
+	if loginMethod == entity.LoginMethodPassword {
+		ip = utils.ClientInfoFromContext(ctx).IP
+	}
+	if err := c.lt.Succeed(ctx, credentials.Email, ip); err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := c.ts.CreateAccessRefreshPairTokens(ctx, credentials, utils.ClientInfoFromContext(ctx), loginMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.VerifyMFAResponse{
+		Tokens: &proto.Tokens{
+			Refresh: refreshToken,
+			Access:  accessToken,
+		},
+	}, nil
+}
+
+func (c CredentialsUseCase) RequestLoginCode(ctx context.Context, req *proto.RequestLoginCodeRequest) error {
+	email, err := c.validateEmail(req.Email)
 	if err != nil {
 		return err
 	}
-	if res {
-		return utils.EmailAlreadyExists
+
+	// Ответ не зависит от того, существует ли почта, чтобы нельзя было перебирать аккаунты
+	code, err := c.lc.CreateLoginCode(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return nil
+	}
+
+	if err := c.lc.SendLoginCodeMailToEmail(email, code); err != nil {
+		log.Printf("Failed send email: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c CredentialsUseCase) SignInWithCode(ctx context.Context, req *proto.SignInWithCodeRequest) (*proto.SignInResponse, error) {
+	email, err := c.validateEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Code) == 0 {
+		return nil, utils.InvalidLoginCode
+	}
+
+	credentials, err := c.lc.VerifyLoginCode(ctx, email, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := c.mfa.IsTOTPEnabled(ctx, credentials.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.completeSignIn(ctx, credentials, entity.LoginMethodEmailCode, mfaEnabled)
+}
+
+func (c CredentialsUseCase) EnrollTOTP(ctx context.Context, req *proto.EnrollTOTPRequest) (*proto.EnrollTOTPResponse, error) {
+	token, err := c.authenticate(ctx, req.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := c.crs.GetCredentialsById(ctx, token.SubjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, uri, err := c.mfa.EnrollTOTP(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.EnrollTOTPResponse{
+		Secret:     secret,
+		OtpauthUri: uri,
+	}, nil
+}
+
+func (c CredentialsUseCase) ConfirmTOTP(ctx context.Context, req *proto.ConfirmTOTPRequest) (*proto.ConfirmTOTPResponse, error) {
+	token, err := c.authenticate(ctx, req.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := c.mfa.ConfirmTOTP(ctx, token.SubjectId, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ConfirmTOTPResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// loginFailure завершает неудачную попытку, уже учтенную Acquire, и при блокировке существующего аккаунта сообщает владельцу на почту
+func (c CredentialsUseCase) loginFailure(email string, ip string, accountLocked bool, lockedUntil time.Time) error {
+	metrics.IncLoginFailure(metrics.LoginScopeAccount)
+	if len(ip) > 0 {
+		metrics.IncLoginFailure(metrics.LoginScopeIP)
+	}
+
+	c.reportLoginLocked(email, accountLocked, lockedUntil)
+
+	if c.ae.Enabled {
+		return utils.InvalidEmailOrPassword
+	}
+	return utils.InvalidCredentials
+}
+
+func (c CredentialsUseCase) reportLoginLocked(email string, accountLocked bool, lockedUntil time.Time) {
+	if !accountLocked {
+		return
+	}
+
+	log.Printf("Security event: sign in locked until %s after repeated failures", lockedUntil)
+	if err := c.crs.SendLoginLockedMailToEmail(email, lockedUntil); err != nil {
+		log.Printf("Failed send email: %s", err)
+	}
+}
+
+func (c CredentialsUseCase) SignUp(ctx context.Context, req *proto.SignUpRequest) error {
+	email, err := c.validateCredentials(req.Credentials, true)
+	if err != nil {
+		return err
 	}
 
+	// Пароль хэшируется до проверки почты, чтобы оба пути занимали сопоставимое время
 	hash, err := c.crs.HashPassword(req.Credentials.Password)
 	if err != nil {
 		return err
 	}
 
+	res, err := c.crs.CheckAlreadyExistsEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if res && c.ae.Enabled {
+		// Клиент получает тот же ответ, что и при регистрации, а владелец почты - предупреждение
+		if err := c.crs.SendRegistrationAttemptMailToEmail(email); err != nil {
+			log.Printf("Failed send email: %s", err)
+			return err
+		}
+		return nil
+	}
+	if res {
+		return utils.EmailAlreadyExists
+	}
+
 	credentials := entity.Credentials{
-		Email:    req.Credentials.Email,
+		Email:    email,
 		Password: hash,
 	}
 
@@ -154,15 +480,188 @@ func (c CredentialsUseCase) SignUp(ctx context.Context, req *proto.SignUpRequest
 		return err
 	}
 
-	// TODO: Продолжить тут
-	code, err := c.crs.SendConfirmRegistrationMailToEmail(req.Credentials.Email)
+	code, err := c.crs.CreateConfirmationCode(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if err := c.crs.SendConfirmRegistrationMailToEmail(email, code); err != nil {
+		log.Printf("Failed send email: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c CredentialsUseCase) ConfirmSignUp(ctx context.Context, req *proto.ConfirmSignUpRequest) error {
+	email, err := c.validateEmail(req.Email)
+	if err != nil {
+		return err
+	}
+
+	return c.crs.ConfirmEmail(ctx, email, req.Code)
+}
+
+func (c CredentialsUseCase) ResendConfirmation(ctx context.Context, req *proto.ResendConfirmationRequest) error {
+	email, err := c.validateEmail(req.Email)
+	if err != nil {
+		return err
+	}
+
+	// Ответ не зависит от того, существует ли почта, чтобы нельзя было перебирать аккаунты
+	code, err := c.crs.ResendConfirmationCode(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return nil
+	}
+
+	if err := c.crs.SendConfirmRegistrationMailToEmail(email, code); err != nil {
+		log.Printf("Failed send email: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c CredentialsUseCase) ResetPassword(ctx context.Context, req *proto.ResetPasswordRequest) error {
+	email, err := c.validateEmail(req.Email)
 	if err != nil {
-		log.Printf("Failed send email: %d", err)
 		return err
 	}
 
-	log.Printf("Confirm code: %s", code)
+	token, err := c.crs.CreatePasswordResetToken(ctx, email)
+	if err != nil {
+		return err
+	}
+	if len(token) == 0 {
+		return nil
+	}
+
+	if err := c.crs.SendPasswordResetMailToEmail(email, token); err != nil {
+		log.Printf("Failed send email: %s", err)
+		return err
+	}
 
 	return nil
 }
 
+func (c CredentialsUseCase) ConfirmReset(ctx context.Context, req *proto.ConfirmResetRequest) error {
+	v := new(validation.Violations)
+	c.val.NewPassword(v, "new_password", req.NewPassword)
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	hash, err := c.crs.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return c.crs.ResetPasswordByToken(ctx, req.Token, hash)
+}
+
+func (c CredentialsUseCase) UpdatePassword(ctx context.Context, req *proto.UpdatePasswordRequest) (*proto.UpdatePasswordResponse, error) {
+	v := new(validation.Violations)
+	c.val.Password(v, "current_password", req.CurrentPassword)
+	c.val.NewPassword(v, "new_password", req.NewPassword)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	token, err := c.authenticate(ctx, req.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := c.crs.GetCredentialsById(ctx, token.SubjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Подбор текущего пароля по украденному access токену ограничивается счетчиком аккаунта, как и вход
+	retryAfter, accountLocked, lockedUntil, err := c.lt.Acquire(ctx, credentials.Email, "")
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		metrics.IncLoginRejected()
+		return nil, utils.LoginLocked(retryAfter)
+	}
+
+	if !c.crs.ValidatePassword(req.CurrentPassword, credentials.Password) {
+		metrics.IncLoginFailure(metrics.LoginScopeAccount)
+		c.reportLoginLocked(credentials.Email, accountLocked, lockedUntil)
+		return nil, utils.InvalidPassword
+	}
+
+	if err := c.lt.Succeed(ctx, credentials.Email, ""); err != nil {
+		return nil, err
+	}
+
+	hash, err := c.crs.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сессии пользователя отзываются вместе со старым паролем, кроме текущей, если ее просят сохранить
+	if !req.KeepCurrentSession {
+		if err := c.crs.UpdatePassword(ctx, credentials.ID, hash); err != nil {
+			return nil, err
+		}
+		return &proto.UpdatePasswordResponse{}, nil
+	}
+
+	// Текущая сессия сохраняет свой id, а ее токены заменяются новой парой
+	accessToken, refreshToken, err := c.ts.UpdatePasswordKeepingSession(ctx, credentials, hash, token.FamilyId, utils.ClientInfoFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.UpdatePasswordResponse{
+		Tokens: &proto.Tokens{
+			Refresh: refreshToken,
+			Access:  accessToken,
+		},
+	}, nil
+}
+
+func (c CredentialsUseCase) ListSessions(ctx context.Context, req *proto.ListSessionsRequest) (*proto.ListSessionsResponse, error) {
+	token, err := c.authenticate(ctx, req.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := c.ss.ListSessions(ctx, token.SubjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ListSessionsResponse{
+		Sessions: make([]*proto.Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, convertor.SessionEntityToProto(session, session.ID == token.FamilyId))
+	}
+
+	return resp, nil
+}
+
+func (c CredentialsUseCase) RevokeSession(ctx context.Context, req *proto.RevokeSessionRequest) error {
+	token, err := c.authenticate(ctx, req.Access)
+	if err != nil {
+		return err
+	}
+
+	return c.ss.RevokeSession(ctx, token.SubjectId, req.SessionId)
+}
+
+func (c CredentialsUseCase) authenticate(ctx context.Context, access string) (dto.TokenDto, error) {
+	jti, err := c.ts.VerifyToken(ctx, access, "access")
+	if err != nil {
+		return dto.TokenDto{}, err
+	}
+
+	return c.ts.GetTokenByJTI(ctx, jti)
+}
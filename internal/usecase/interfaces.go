@@ -4,6 +4,7 @@ import (
 	"AuthService/internal/dto"
 	"AuthService/internal/entity"
 	"context"
+	"time"
 )
 
 type credentialsService interface {
@@ -11,16 +12,57 @@ type credentialsService interface {
 	CheckAlreadyExistsEmail(ctx context.Context, email string) (bool, error)
 	HashPassword(password string) (string, error)
 	ValidatePassword(password, hash string) bool
+	ValidateDummyPassword(password string)
 	CreateCredentials(ctx context.Context, credentials entity.Credentials) error
 	GetCredentialsByEmail(ctx context.Context, email string) (entity.Credentials, error)
-	SendConfirmRegistrationMailToEmail(email string) (string, error)
+	SendConfirmRegistrationMailToEmail(email string, code string) error
+	CreateConfirmationCode(ctx context.Context, email string) (string, error)
+	ConfirmEmail(ctx context.Context, email string, code string) error
+	ResendConfirmationCode(ctx context.Context, email string) (string, error)
+	CreatePasswordResetToken(ctx context.Context, email string) (string, error)
+	SendPasswordResetMailToEmail(email string, token string) error
+	SendRegistrationAttemptMailToEmail(email string) error
+	ResetPasswordByToken(ctx context.Context, token string, passwordHash string) error
+	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+	SendSecurityAlertMailToEmail(email string, body string) error
+	SendLoginLockedMailToEmail(email string, lockedUntil time.Time) error
+}
+
+type mfaService interface {
+	EnrollTOTP(ctx context.Context, credentials entity.Credentials) (string, string, error)
+	ConfirmTOTP(ctx context.Context, credentialsId int64, code string) ([]string, error)
+	IsTOTPEnabled(ctx context.Context, credentialsId int64) (bool, error)
+	CreateChallenge(ctx context.Context, credentialsId int64, loginMethod string) (string, time.Time, error)
+	GetChallengeCredentialsId(ctx context.Context, token string) (int64, error)
+	VerifyChallenge(ctx context.Context, token string, code string) (int64, string, error)
+}
+
+type loginCodeService interface {
+	CreateLoginCode(ctx context.Context, email string) (string, error)
+	SendLoginCodeMailToEmail(email string, code string) error
+	VerifyLoginCode(ctx context.Context, email string, code string) (entity.Credentials, error)
+}
+
+type loginThrottleService interface {
+	Acquire(ctx context.Context, email string, ip string) (time.Duration, bool, time.Time, error)
+	Succeed(ctx context.Context, email string, ip string) error
 }
 
 type tokensService interface {
 	RevokeTokenByJTI(ctx context.Context, jti string) error
+	RevokeSessionByPair(ctx context.Context, accessJti string, refreshJti string) error
 	RevokeAllTokensWithBySubjectId(ctx context.Context, subjectId int64) error
 	GetTokenByJTI(ctx context.Context, jti string) (dto.TokenDto, error)
 	VerifyToken(ctx context.Context, tokenString string, expectedType string) (string, error)
-	CreateAccessRefreshPairTokens(ctx context.Context, credentialsId int64, email string) (string, string, error)
+	CreateAccessRefreshPairTokens(ctx context.Context, credentials entity.Credentials, client entity.ClientInfo, loginMethod string) (string, string, error)
+	UpdatePasswordKeepingSession(ctx context.Context, credentials entity.Credentials, passwordHash string, familyId string, client entity.ClientInfo) (string, string, error)
+	ParseToken(tokenString string, expectedType string) (string, error)
+	VerifyAccessTokenClaims(ctx context.Context, tokenString string) (entity.TokenClaims, error)
+	VerifyAccessTokensClaims(ctx context.Context, tokenStrings []string) ([]entity.TokenClaims, []error, error)
+	RotateRefreshToken(ctx context.Context, jti string, client entity.ClientInfo) (dto.TokenDto, string, string, error)
 }
 
+type sessionsService interface {
+	ListSessions(ctx context.Context, subjectId int64) ([]entity.Session, error)
+	RevokeSession(ctx context.Context, subjectId int64, sessionId string) error
+}
@@ -1,19 +1,99 @@
 package utils
 
 import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const ErrorDomain = "auth.service"
+
+// Причины ошибок токена, передаваемые клиенту в errdetails.ErrorInfo
+const (
+	TokenReasonInvalid             = "TOKEN_INVALID"
+	TokenReasonExpired             = "TOKEN_EXPIRED"
+	TokenReasonBadSignature        = "TOKEN_BAD_SIGNATURE"
+	TokenReasonAlgorithmNotAllowed = "TOKEN_ALGORITHM_NOT_ALLOWED"
+	TokenReasonInvalidClaims       = "TOKEN_INVALID_CLAIMS"
+	TokenReasonWrongType           = "TOKEN_WRONG_TYPE"
+	TokenReasonRevoked             = "TOKEN_REVOKED"
+	TokenReasonReused              = "TOKEN_REUSED"
 )
 
+const LoginReasonLocked = "LOGIN_LOCKED"
+
 var (
 	// TOKEN ERRORS
-	RevokedToken = status.Error(codes.Unauthenticated, "Token revoked")
-	InvalidToken = status.Error(codes.Unauthenticated, "Invalid token")
+	RevokedToken             = tokenError(TokenReasonRevoked, "Token revoked")
+	InvalidToken             = tokenError(TokenReasonInvalid, "Invalid token")
+	ExpiredToken             = tokenError(TokenReasonExpired, "Token expired")
+	BadTokenSignature        = tokenError(TokenReasonBadSignature, "Invalid token signature")
+	TokenAlgorithmNotAllowed = tokenError(TokenReasonAlgorithmNotAllowed, "Token signing algorithm not allowed")
+	InvalidTokenClaims       = tokenError(TokenReasonInvalidClaims, "Invalid token claims")
+	WrongTokenType           = tokenError(TokenReasonWrongType, "Wrong token type")
+	RefreshTokenReused       = tokenError(TokenReasonReused, "Refresh token reused, session revoked")
 
 	// CREDENTIALS ERRORS
 	EmailAlreadyExists = status.Error(codes.AlreadyExists, "Email already exists")
 	InvalidCredentials = status.Error(codes.NotFound, "Credentials not found")
+	EmailNotVerified   = status.Error(codes.FailedPrecondition, "Email not verified")
+	InvalidPassword    = status.Error(codes.PermissionDenied, "Invalid current password")
+	// Единая ошибка входа в режиме защиты от перебора аккаунтов
+	InvalidEmailOrPassword = status.Error(codes.Unauthenticated, "Invalid email or password")
+
+	// CONFIRMATION ERRORS
+	InvalidConfirmationCode = status.Error(codes.InvalidArgument, "Invalid confirmation code")
+
+	// SESSION ERRORS
+	SessionNotFound = status.Error(codes.NotFound, "Session not found")
+
+	// LOGIN CODE ERRORS
+	InvalidLoginCode         = status.Error(codes.Unauthenticated, "Invalid or expired login code")
+	TooManyLoginCodeAttempts = status.Error(codes.ResourceExhausted, "Too many login code attempts")
+
+	// MFA ERRORS
+	MFANotConfigured    = status.Error(codes.FailedPrecondition, "Two-factor authentication is not configured")
+	TOTPAlreadyEnabled  = status.Error(codes.FailedPrecondition, "TOTP already enabled")
+	TOTPNotEnrolled     = status.Error(codes.FailedPrecondition, "TOTP enrollment not started")
+	InvalidMFACode      = status.Error(codes.Unauthenticated, "Invalid MFA code")
+	InvalidMFAChallenge = status.Error(codes.Unauthenticated, "Invalid or expired MFA challenge")
+	TooManyMFAAttempts  = status.Error(codes.ResourceExhausted, "Too many MFA attempts")
+
+	// PASSWORD RESET ERRORS
+	InvalidResetToken = status.Error(codes.InvalidArgument, "Invalid or expired reset token")
+
+	// BATCH ERRORS
+	TooManyTokens = status.Error(codes.InvalidArgument, "Too many tokens in request")
 
 	// OTHER ERRORS
 	InternalServerError = status.Error(codes.Internal, "Internal server error")
 )
+
+// LoginLocked - вход временно запрещен; RetryInfo сообщает, через сколько можно повторить попытку
+func LoginLocked(retryAfter time.Duration) error {
+	retryAfter = retryAfter.Round(time.Second) + time.Second
+	st, err := status.New(codes.ResourceExhausted, "Too many failed sign in attempts").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+		&errdetails.ErrorInfo{Reason: LoginReasonLocked, Domain: ErrorDomain},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, "Too many failed sign in attempts")
+	}
+
+	return st.Err()
+}
+
+func tokenError(reason string, message string) error {
+	st, err := status.New(codes.Unauthenticated, message).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorDomain,
+	})
+	if err != nil {
+		return status.Error(codes.Unauthenticated, message)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"AuthService/internal/config"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Ограничение длины адреса из RFC 5321
+const maxEmailLength = 254
+
+// Violations накапливает ошибки полей запроса, чтобы вернуть их клиенту все сразу.
+type Violations struct {
+	fields []*errdetails.BadRequest_FieldViolation
+}
+
+func (v *Violations) Add(field string, description string) {
+	v.fields = append(v.fields, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+// Err возвращает InvalidArgument с деталями errdetails.BadRequest или nil, если нарушений нет.
+func (v *Violations) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+
+	st, err := status.New(codes.InvalidArgument, "Invalid request").WithDetails(&errdetails.BadRequest{
+		FieldViolations: v.fields,
+	})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid request")
+	}
+
+	return st.Err()
+}
+
+type Validator struct {
+	policy *config.PasswordPolicyConfig
+}
+
+func NewValidator(policy *config.PasswordPolicyConfig) *Validator {
+	return &Validator{
+		policy: policy,
+	}
+}
+
+// Email проверяет синтаксис адреса и возвращает его в нормализованном виде: без пробелов по краям и в нижнем регистре.
+func (val *Validator) Email(v *Violations, field string, email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	switch {
+	case len(email) == 0:
+		v.Add(field, "Email is required")
+	case len(email) > maxEmailLength:
+		v.Add(field, fmt.Sprintf("Email must be at most %d characters", maxEmailLength))
+	default:
+		// Допускается только голый адрес, без отображаемого имени и угловых скобок
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@")+1:], ".") {
+			v.Add(field, "Email is not a valid address")
+		}
+	}
+
+	return email
+}
+
+// Password проверяет пароль, который сверяется с уже сохраненным: политика к нему не применяется.
+func (val *Validator) Password(v *Violations, field string, password string) {
+	switch {
+	case len(password) == 0:
+		v.Add(field, "Password is required")
+	case len(password) > config.BcryptMaxPasswordLength:
+		v.Add(field, fmt.Sprintf("Password must be at most %d bytes", config.BcryptMaxPasswordLength))
+	}
+}
+
+// NewPassword проверяет новый пароль по политике паролей.
+func (val *Validator) NewPassword(v *Violations, field string, password string) {
+	if !utf8.ValidString(password) {
+		v.Add(field, "Password must be valid UTF-8")
+		return
+	}
+
+	if utf8.RuneCountInString(password) < val.policy.MinLength {
+		v.Add(field, fmt.Sprintf("Password must be at least %d characters", val.policy.MinLength))
+	}
+	if len(password) > val.policy.MaxLength {
+		v.Add(field, fmt.Sprintf("Password must be at most %d bytes", val.policy.MaxLength))
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSpecial bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSpecial = true
+		}
+	}
+
+	if val.policy.RequireLower && !hasLower {
+		v.Add(field, "Password must contain a lowercase letter")
+	}
+	if val.policy.RequireUpper && !hasUpper {
+		v.Add(field, "Password must contain an uppercase letter")
+	}
+	if val.policy.RequireDigit && !hasDigit {
+		v.Add(field, "Password must contain a digit")
+	}
+	if val.policy.RequireSpecial && !hasSpecial {
+		v.Add(field, "Password must contain a special character")
+	}
+}
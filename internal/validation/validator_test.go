@@ -0,0 +1,165 @@
+package validation
+
+import (
+	"AuthService/internal/config"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestValidator() *Validator {
+	return NewValidator(&config.PasswordPolicyConfig{
+		MinLength:      8,
+		MaxLength:      config.BcryptMaxPasswordLength,
+		RequireLower:   true,
+		RequireUpper:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+	})
+}
+
+// violations возвращает описания нарушений из ошибки Violations.Err
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("error = %v, want InvalidArgument", err)
+	}
+
+	var descriptions []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, field := range badRequest.FieldViolations {
+				descriptions = append(descriptions, field.Description)
+			}
+		}
+	}
+	return descriptions
+}
+
+func TestValidatorEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     string
+		want      string
+		violation string
+	}{
+		{name: "Plain", email: "user@example.com", want: "user@example.com"},
+		{name: "Normalized", email: "  User@Example.COM ", want: "user@example.com"},
+		{name: "Empty", email: "   ", violation: "Email is required"},
+		{name: "TooLong", email: strings.Repeat("a", 250) + "@example.com", violation: "Email must be at most 254 characters"},
+		{name: "NoAt", email: "user.example.com", violation: "Email is not a valid address"},
+		{name: "NoDotInDomain", email: "user@localhost", violation: "Email is not a valid address"},
+		{name: "DisplayName", email: "User <user@example.com>", violation: "Email is not a valid address"},
+	}
+
+	val := newTestValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := new(Violations)
+			got := val.Email(v, "email", tt.email)
+
+			descriptions := violations(t, v.Err())
+			if len(tt.violation) > 0 {
+				if !slices.Equal(descriptions, []string{tt.violation}) {
+					t.Fatalf("violations = %v, want [%s]", descriptions, tt.violation)
+				}
+				return
+			}
+			if len(descriptions) > 0 {
+				t.Fatalf("unexpected violations: %v", descriptions)
+			}
+			if got != tt.want {
+				t.Fatalf("Email(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorNewPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		violations []string
+	}{
+		{name: "Valid", password: "Passw0rd!"},
+		{name: "ValidUnicode", password: "Пароль1!x"},
+		{name: "TooShort", password: "Pa0!", violations: []string{"Password must be at least 8 characters"}},
+		{name: "NoLower", password: "PASSW0RD!", violations: []string{"Password must contain a lowercase letter"}},
+		{name: "NoUpper", password: "passw0rd!", violations: []string{"Password must contain an uppercase letter"}},
+		{name: "NoDigit", password: "Password!", violations: []string{"Password must contain a digit"}},
+		{name: "NoSpecial", password: "Passw0rdd", violations: []string{"Password must contain a special character"}},
+		{name: "SpaceIsSpecial", password: "Passw0rd x"},
+		{name: "BcryptLimit", password: "Aa1!" + strings.Repeat("x", config.BcryptMaxPasswordLength-4)},
+		{
+			name:       "OverBcryptLimit",
+			password:   "Aa1!" + strings.Repeat("x", config.BcryptMaxPasswordLength-3),
+			violations: []string{"Password must be at most 72 bytes"},
+		},
+		{
+			// Длина в символах проходит, но в байтах UTF-8 пароль длиннее лимита bcrypt
+			name:       "OverBcryptLimitInBytes",
+			password:   "Aa1!" + strings.Repeat("ж", 35),
+			violations: []string{"Password must be at most 72 bytes"},
+		},
+		{name: "InvalidUTF8", password: "Passw0rd!\xff", violations: []string{"Password must be valid UTF-8"}},
+		{
+			name:     "Empty",
+			password: "",
+			violations: []string{
+				"Password must be at least 8 characters",
+				"Password must contain a lowercase letter",
+				"Password must contain an uppercase letter",
+				"Password must contain a digit",
+				"Password must contain a special character",
+			},
+		},
+	}
+
+	val := newTestValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := new(Violations)
+			val.NewPassword(v, "password", tt.password)
+
+			if descriptions := violations(t, v.Err()); !slices.Equal(descriptions, tt.violations) {
+				t.Fatalf("violations = %v, want %v", descriptions, tt.violations)
+			}
+		})
+	}
+}
+
+func TestValidatorPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		violations []string
+	}{
+		// К уже сохраненному паролю политика не применяется
+		{name: "WeakButPresent", password: "a"},
+		{name: "Empty", password: "", violations: []string{"Password is required"}},
+		{name: "BcryptLimit", password: strings.Repeat("a", config.BcryptMaxPasswordLength)},
+		{name: "OverBcryptLimit", password: strings.Repeat("a", config.BcryptMaxPasswordLength+1), violations: []string{"Password must be at most 72 bytes"}},
+	}
+
+	val := newTestValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := new(Violations)
+			val.Password(v, "password", tt.password)
+
+			if descriptions := violations(t, v.Err()); !slices.Equal(descriptions, tt.violations) {
+				t.Fatalf("violations = %v, want %v", descriptions, tt.violations)
+			}
+		})
+	}
+}